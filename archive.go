@@ -0,0 +1,111 @@
+package sysinfo
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"io/fs"
+	"path"
+	"sort"
+)
+
+// Capture writes every file that Detect reads to w as a zip
+// archive.
+//
+// The archive also includes /proc/self/status, which records
+// the CPUs the capturing process was allowed to run on.
+//
+// Use DetectFromArchive to run the same detection against
+// the archive, for example on a different host. Files that
+// cannot be read, such as those that require root, are not
+// included.
+//
+// Capture is only supported on Linux.
+func Capture(w io.Writer) error {
+	return captureHost(w)
+}
+
+// DetectFromArchive finds the host information in an archive
+// written by Capture.
+//
+// It works on every platform.
+func DetectFromArchive(r io.Reader) (Info, error) {
+	buf, err := io.ReadAll(r)
+	if err != nil {
+		return Info{}, err
+	}
+	z, err := zip.NewReader(bytes.NewReader(buf), int64(len(buf)))
+	if err != nil {
+		return Info{}, err
+	}
+	v := detectFS(z, readAllowed(z))
+	v.SchemaVersion = SchemaVersion
+	return v, nil
+}
+
+// capture runs the detection against fsys and writes the
+// files and directories it read to w.
+func capture(w io.Writer, fsys fs.FS) error {
+	rec := &recordFS{
+		FS:    fsys,
+		files: make(map[string][]byte),
+		dirs:  make(map[string]bool),
+	}
+	detectFS(rec, readAllowed(rec))
+
+	var names []string
+	for name := range rec.dirs {
+		names = append(names, name+"/")
+	}
+	for name := range rec.files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	z := zip.NewWriter(w)
+	for _, name := range names {
+		if name[len(name)-1] == '/' {
+			if _, err := z.Create(name); err != nil {
+				return err
+			}
+			continue
+		}
+		f, err := z.Create(name)
+		if err != nil {
+			return err
+		}
+		if _, err := f.Write(rec.files[name]); err != nil {
+			return err
+		}
+	}
+	return z.Close()
+}
+
+// recordFS is an fs.FS that records the files and directories
+// read from it.
+type recordFS struct {
+	fs.FS
+	files map[string][]byte
+	dirs  map[string]bool
+}
+
+var (
+	_ fs.ReadFileFS = (*recordFS)(nil)
+	_ fs.ReadDirFS  = (*recordFS)(nil)
+)
+
+func (r *recordFS) ReadFile(name string) ([]byte, error) {
+	buf, err := fs.ReadFile(r.FS, name)
+	if err == nil {
+		r.files[name] = buf
+	}
+	return buf, err
+}
+
+func (r *recordFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	ents, err := fs.ReadDir(r.FS, name)
+	if err == nil && name != "." {
+		r.dirs[path.Clean(name)] = true
+	}
+	return ents, err
+}
@@ -0,0 +1,101 @@
+package sysinfo
+
+import (
+	"archive/zip"
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"sort"
+	"testing"
+	"testing/fstest"
+)
+
+func TestCapture(t *testing.T) {
+	cpuinfo, err := os.ReadFile(filepath.Join("testdata", "raspberry_pi_4b"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	fsys := fstest.MapFS{
+		"proc/cpuinfo":                          {Data: cpuinfo},
+		"proc/self/status":                      {Data: []byte("Name:\tsysinfo\nCpus_allowed_list:\t0-3\n")},
+		"proc/loadavg":                          {Data: []byte("0.20 0.18 0.12 1/80 11206\n")},
+		"sys/devices/system/cpu/present":        {Data: []byte("0-3\n")},
+		"sys/devices/system/cpu/online":         {Data: []byte("0-3\n")},
+		"sys/class/thermal/thermal_zone0/type":  {Data: []byte("cpu-thermal\n")},
+		"sys/class/thermal/thermal_zone0/temp":  {Data: []byte("45277\n")},
+		"sys/class/dmi/id/sys_vendor":           {Data: []byte("QEMU\n")},
+		"proc/device-tree/model":                {Data: []byte("Raspberry Pi 4 Model B Rev 1.1\x00")},
+		"proc/device-tree/compatible":           {Data: []byte("raspberrypi,4-model-b\x00brcm,bcm2711\x00")},
+		"sys/devices/system/cpu/cpuidle/README": {Data: []byte("not read\n")},
+		"etc/passwd":                            {Data: []byte("root:x:0:0::/root:/bin/sh\n")},
+	}
+
+	var buf bytes.Buffer
+	if err := capture(&buf, fsys); err != nil {
+		t.Fatal(err)
+	}
+	z, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, f := range z.File {
+		names = append(names, f.Name)
+	}
+	for _, name := range []string{
+		"proc/cpuinfo",
+		"proc/self/status",
+		"sys/class/thermal/",
+		"sys/class/thermal/thermal_zone0/temp",
+	} {
+		if i := sort.SearchStrings(names, name); i == len(names) || names[i] != name {
+			t.Fatalf("expected %q in %q", name, names)
+		}
+	}
+	for _, name := range names {
+		if name == "etc/passwd" || name == "sys/devices/system/cpu/cpuidle/README" {
+			t.Fatalf("unexpected %q in archive", name)
+		}
+	}
+
+	got, err := DetectFromArchive(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := detectFS(fsys, readAllowed(fsys))
+	want.SchemaVersion = SchemaVersion
+	got.Thermal.fsys, want.Thermal.fsys = nil, nil
+	got.Power.fsys, want.Power.fsys = nil, nil
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %#v, got %#v", want, got)
+	}
+	if got.Masks.Allowed.String() != "0-3" || len(got.Thermal.Sensors) != 1 {
+		t.Fatalf("unexpected info: %#v", got)
+	}
+}
+
+func TestCaptureHost(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("only supported on linux")
+	}
+	var buf bytes.Buffer
+	if err := Capture(&buf); err != nil {
+		t.Fatal(err)
+	}
+	got, err := DetectFromArchive(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts := DiffOptions{FreqTolerance: 1e9}
+	if changes := opts.Diff(Detect(), got); len(changes) != 0 {
+		t.Fatalf("expected no changes, got %v", changes)
+	}
+}
+
+func TestDetectFromArchiveInvalid(t *testing.T) {
+	if _, err := DetectFromArchive(bytes.NewReader([]byte("not a zip file"))); err == nil {
+		t.Fatal("expected an error")
+	}
+}
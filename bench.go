@@ -0,0 +1,122 @@
+package sysinfo
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// BenchConfig returns configuration lines for Go benchmark
+// results that describe the host, so that tools like
+// benchstat can group results by hardware.
+//
+// The keys are
+//
+//	cpu        the CPU model name, or the vendor and part names
+//	microarch  the CPU microarchitecture
+//	cores      the number of physical cores
+//	l3         the size of the L3 cache
+//	governor   the cpufreq scaling governor
+//	smt        the state of simultaneous multithreading
+//	kernel     the kernel release
+//
+// Values that are not known are omitted. Hosts with several
+// kinds of CPUs, such as Arm big.LITTLE systems, list each
+// kind separated by commas.
+func (v Info) BenchConfig() []Pair {
+	var lines []Pair
+	add := func(key, value string) {
+		if value != "" {
+			lines = append(lines, Pair{Key: key, Value: value})
+		}
+	}
+
+	cpus := v.onlineCPUs()
+	var names, archs, govs []string
+	for _, c := range cpus {
+		name := c.ModelName
+		if name == "" && c.Part != 0 {
+			name = partName(c.Part)
+			if c.Impl != 0 {
+				name = lscpuVendor(c.Impl) + " " + name
+			}
+		}
+		arch := c.MicroArch
+		if arch == "" && c.VendorID == "" {
+			arch = partName(c.Part)
+		}
+		names = appendUnique(names, name)
+		archs = appendUnique(archs, arch)
+		govs = appendUnique(govs, c.Governor)
+	}
+	add("cpu", strings.Join(names, ", "))
+	add("microarch", strings.Join(archs, ", "))
+	if t := v.lscpuTopology(); t.sockets > 0 {
+		add("cores", strconv.Itoa(t.cores*t.sockets))
+	}
+	if len(cpus) > 0 && cpus[0].Cache.L3 != 0 {
+		add("l3", formatBytes(cpus[0].Cache.L3))
+	}
+	add("governor", strings.Join(govs, ", "))
+	add("smt", v.Security.SMT)
+	add("kernel", v.Kernel)
+	return lines
+}
+
+// WriteBenchConfig writes the configuration lines returned by
+// BenchConfig to w in the Go benchmark format, for example
+//
+//	cpu: Intel(R) Xeon(R) Gold 6230 CPU @ 2.10GHz
+//	cores: 40
+//	governor: performance
+//
+// Lines written before the benchmark results apply to every
+// result that follows, so it is typically called from
+// TestMain:
+//
+//	func TestMain(m *testing.M) {
+//		flag.Parse()
+//		if flag.Lookup("test.bench").Value.String() != "" {
+//			sysinfo.Detect().WriteBenchConfig(os.Stdout)
+//		}
+//		os.Exit(m.Run())
+//	}
+//
+// The testing package prints its own "cpu" line, but not on
+// most Arm hosts.
+func (v Info) WriteBenchConfig(w io.Writer) error {
+	var b strings.Builder
+	for _, p := range v.BenchConfig() {
+		fmt.Fprintf(&b, "%s: %s\n", p.Key, p.Value)
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// appendUnique appends s to list if s is not empty and not
+// already in list.
+func appendUnique(list []string, s string) []string {
+	if s == "" || hasString(list, s) {
+		return list
+	}
+	return append(list, s)
+}
+
+// formatBytes formats n using the largest binary unit that
+// divides it, for example "32MiB".
+func formatBytes(n int) string {
+	for _, u := range []struct {
+		suffix string
+		size   int
+	}{
+		{"GiB", 1 << 30},
+		{"MiB", 1 << 20},
+		{"KiB", 1 << 10},
+	} {
+		if n >= u.size && n%u.size == 0 {
+			return strconv.Itoa(n/u.size) + u.suffix
+		}
+	}
+	return strconv.Itoa(n) + "B"
+}
@@ -0,0 +1,50 @@
+package sysinfo
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestBenchConfig(t *testing.T) {
+	v := readTestInfo(t, "google_pixel_6")
+	for i := range v.CPUs {
+		v.CPUs[i].Governor = "schedutil"
+	}
+	v.Kernel = "5.10.107-android13-4"
+	var buf bytes.Buffer
+	if err := v.WriteBenchConfig(&buf); err != nil {
+		t.Fatal(err)
+	}
+	const want = `cpu: ARM Cortex-A55, ARM Cortex-A76, ARM Cortex-X1
+microarch: Cortex-A55, Cortex-A76, Cortex-X1
+cores: 8
+governor: schedutil
+kernel: 5.10.107-android13-4
+`
+	if got := buf.String(); got != want {
+		t.Fatalf("expected\n%s\ngot\n%s", want, got)
+	}
+
+	v = lscpuTestInfo()
+	v.CPUs[0].ModelName = "Intel(R) Xeon(R) Gold 6230 CPU @ 2.10GHz"
+	v.CPUs[1].Governor = "performance"
+	v.Security.SMT = "on"
+	got := make(map[string]string)
+	for _, p := range v.BenchConfig() {
+		got[p.Key] = p.Value
+	}
+	for k, want := range map[string]string{
+		"cpu":      "Intel(R) Xeon(R) Gold 6230 CPU @ 2.10GHz",
+		"cores":    "4",
+		"l3":       "32MiB",
+		"governor": "performance",
+		"smt":      "on",
+	} {
+		if got[k] != want {
+			t.Errorf("%s: expected %q, got %q", k, want, got[k])
+		}
+	}
+	if _, ok := got["microarch"]; ok {
+		t.Errorf("unexpected microarch: %q", got["microarch"])
+	}
+}
@@ -0,0 +1,213 @@
+package sysinfo
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Board describes a single-board computer, such as
+// a Raspberry Pi.
+//
+// On Linux, this information is read from the trailer of
+// /proc/cpuinfo and from /proc/device-tree.
+type Board struct {
+	// Model is the board's model string.
+	//
+	// Matches: Model, /proc/device-tree/model
+	Model string `json:"model,omitempty"`
+	// Name is the product name decoded from the revision
+	// code, for example "Raspberry Pi 4 Model B".
+	Name string `json:"name,omitempty"`
+	// Manufacturer is the board manufacturer decoded from the
+	// revision code, for example "Sony UK".
+	Manufacturer string `json:"manufacturer,omitempty"`
+	// SoC is the system on a chip decoded from the revision
+	// code or device tree, for example "BCM2711".
+	SoC string `json:"soc,omitempty"`
+	// SoCVendor is the SoC's vendor decoded from the device
+	// tree, for example "Broadcom".
+	SoCVendor string `json:"soc_vendor,omitempty"`
+	// Compatible is the board's device tree compatible list,
+	// from most to least specific.
+	//
+	// Matches: /proc/device-tree/compatible
+	Compatible []string `json:"compatible,omitempty"`
+	// Memory is the size in bytes of the board's memory
+	// decoded from the revision code.
+	Memory uint64 `json:"memory,omitempty"`
+	// Revision is the PCB revision decoded from the revision
+	// code, for example "1.1".
+	Revision string `json:"revision,omitempty"`
+	// RevisionCode is the board's raw revision code.
+	//
+	// Matches: Revision
+	RevisionCode string `json:"revision_code,omitempty"`
+	// Hardware is the hardware reported by the kernel.
+	//
+	// On a Raspberry Pi, this is often "BCM2835" regardless
+	// of the actual SoC. Prefer SoC.
+	//
+	// Matches: Hardware
+	Hardware string `json:"hardware,omitempty"`
+	// Serial is the board serial number.
+	//
+	// Matches: Serial, /proc/device-tree/serial-number
+	Serial string `json:"serial,omitempty"`
+}
+
+// scanBoard fills in o.Board from the trailer of
+// /proc/cpuinfo.
+func scanBoard(o *Info) {
+	b := &o.Board
+	for _, p := range o.Misc {
+		switch p.Key {
+		case "Model":
+			b.Model = p.Value
+		case "Revision":
+			b.RevisionCode = p.Value
+		case "Hardware":
+			b.Hardware = p.Value
+		case "Serial":
+			b.Serial = p.Value
+		}
+	}
+	decodeBoard(b)
+}
+
+// decodeBoard decodes b.RevisionCode if the board is
+// a Raspberry Pi.
+func decodeBoard(b *Board) {
+	if b.RevisionCode == "" || b.Name != "" {
+		return
+	}
+	if strings.HasPrefix(b.Model, "Raspberry Pi") ||
+		strings.HasPrefix(b.Hardware, "BCM") {
+		decodePiRevision(b)
+	}
+}
+
+// decodePiRevision decodes b.RevisionCode as a Raspberry Pi
+// revision code.
+//
+// New-style revision codes have the format
+//
+//	NOQuuuWuFMMMCCCCPPPPTTTTTTTTRRRR
+//
+// where F is set. Otherwise, the code is an old-style code
+// from the fixed table of original Raspberry Pi boards.
+//
+// See https://www.raspberrypi.com/documentation/computers/raspberry-pi.html#raspberry-pi-revision-codes
+func decodePiRevision(b *Board) bool {
+	code, err := strconv.ParseUint(b.RevisionCode, 16, 32)
+	if err != nil {
+		return false
+	}
+	if code&(1<<23) == 0 {
+		return decodeOldPiRevision(b, code)
+	}
+	var (
+		rev  = code & 0xf
+		typ  = (code >> 4) & 0xff
+		proc = (code >> 12) & 0xf
+		mfr  = (code >> 16) & 0xf
+		mem  = (code >> 20) & 0x7
+	)
+	name, ok := piModels[typ]
+	if !ok {
+		return false
+	}
+	b.Name = "Raspberry Pi " + name
+	b.Revision = "1." + strconv.Itoa(int(rev))
+	if int(proc) < len(piSoCs) {
+		b.SoC = piSoCs[proc]
+	}
+	if int(mfr) < len(piManufacturers) {
+		b.Manufacturer = piManufacturers[mfr]
+	}
+	b.Memory = uint64(256<<mem) << 20
+	return true
+}
+
+var piModels = map[uint64]string{
+	0x00: "Model A",
+	0x01: "Model B",
+	0x02: "Model A+",
+	0x03: "Model B+",
+	0x04: "2 Model B",
+	0x05: "Alpha",
+	0x06: "Compute Module 1",
+	0x08: "3 Model B",
+	0x09: "Zero",
+	0x0a: "Compute Module 3",
+	0x0c: "Zero W",
+	0x0d: "3 Model B+",
+	0x0e: "3 Model A+",
+	0x10: "Compute Module 3+",
+	0x11: "4 Model B",
+	0x12: "Zero 2 W",
+	0x13: "400",
+	0x14: "Compute Module 4",
+	0x15: "Compute Module 4S",
+	0x17: "5",
+	0x18: "Compute Module 5",
+	0x19: "500",
+	0x1a: "Compute Module 5 Lite",
+}
+
+var piSoCs = []string{
+	"BCM2835",
+	"BCM2836",
+	"BCM2837",
+	"BCM2711",
+	"BCM2712",
+}
+
+var piManufacturers = []string{
+	"Sony UK",
+	"Egoman",
+	"Embest",
+	"Sony Japan",
+	"Embest",
+	"Stadium",
+}
+
+// decodeOldPiRevision decodes an old-style revision code.
+func decodeOldPiRevision(b *Board, code uint64) bool {
+	// Bit 24 is set if the warranty has been voided by
+	// overclocking.
+	code &^= 1 << 24
+
+	type rev struct {
+		model, rev, mfr string
+		mem             int
+	}
+	revs := map[uint64]rev{
+		0x0002: {"Model B", "1.0", "Egoman", 256},
+		0x0003: {"Model B", "1.0", "Egoman", 256},
+		0x0004: {"Model B", "2.0", "Sony UK", 256},
+		0x0005: {"Model B", "2.0", "Qisda", 256},
+		0x0006: {"Model B", "2.0", "Egoman", 256},
+		0x0007: {"Model A", "2.0", "Egoman", 256},
+		0x0008: {"Model A", "2.0", "Sony UK", 256},
+		0x0009: {"Model A", "2.0", "Qisda", 256},
+		0x000d: {"Model B", "2.0", "Egoman", 512},
+		0x000e: {"Model B", "2.0", "Sony UK", 512},
+		0x000f: {"Model B", "2.0", "Egoman", 512},
+		0x0010: {"Model B+", "1.2", "Sony UK", 512},
+		0x0011: {"Compute Module 1", "1.0", "Sony UK", 512},
+		0x0012: {"Model A+", "1.1", "Sony UK", 256},
+		0x0013: {"Model B+", "1.2", "Embest", 512},
+		0x0014: {"Compute Module 1", "1.0", "Embest", 512},
+		0x0015: {"Model A+", "1.1", "Embest", 256},
+	}
+	r, ok := revs[code]
+	if !ok {
+		return false
+	}
+	b.Name = "Raspberry Pi " + r.model
+	b.Revision = r.rev
+	b.Manufacturer = r.mfr
+	b.SoC = "BCM2835"
+	b.Memory = uint64(r.mem) << 20
+	return true
+}
@@ -0,0 +1,30 @@
+package sysinfo
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDecodePiRevision(t *testing.T) {
+	for _, tc := range []struct {
+		code string
+		want Board
+	}{
+		{"c03111", Board{Name: "Raspberry Pi 4 Model B", Manufacturer: "Sony UK", SoC: "BCM2711", Memory: 4 << 30, Revision: "1.1"}},
+		{"a020d3", Board{Name: "Raspberry Pi 3 Model B+", Manufacturer: "Sony UK", SoC: "BCM2837", Memory: 1 << 30, Revision: "1.3"}},
+		{"a22082", Board{Name: "Raspberry Pi 3 Model B", Manufacturer: "Embest", SoC: "BCM2837", Memory: 1 << 30, Revision: "1.2"}},
+		{"902120", Board{Name: "Raspberry Pi Zero 2 W", Manufacturer: "Sony UK", SoC: "BCM2837", Memory: 512 << 20, Revision: "1.0"}},
+		{"d04170", Board{Name: "Raspberry Pi 5", Manufacturer: "Sony UK", SoC: "BCM2712", Memory: 8 << 30, Revision: "1.0"}},
+		{"000e", Board{Name: "Raspberry Pi Model B", Manufacturer: "Sony UK", SoC: "BCM2835", Memory: 512 << 20, Revision: "2.0"}},
+		{"1000013", Board{Name: "Raspberry Pi Model B+", Manufacturer: "Embest", SoC: "BCM2835", Memory: 512 << 20, Revision: "1.2"}},
+		{"0001", Board{}},
+		{"zz", Board{}},
+	} {
+		got := Board{RevisionCode: tc.code}
+		decodePiRevision(&got)
+		tc.want.RevisionCode = tc.code
+		if !reflect.DeepEqual(got, tc.want) {
+			t.Fatalf("%s: expected %#v, got %#v", tc.code, tc.want, got)
+		}
+	}
+}
@@ -0,0 +1,333 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+
+	"github.com/ericlagergren/sysinfo"
+)
+
+// object is a JSON object that preserves the order of its
+// members, which is the order of the Info struct fields.
+type object []member
+
+type member struct {
+	key string
+	val interface{}
+}
+
+func (o object) get(key string) (interface{}, bool) {
+	for _, m := range o {
+		if m.key == key {
+			return m.val, true
+		}
+	}
+	return nil, false
+}
+
+func (o object) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, m := range o {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		k, err := json.Marshal(m.key)
+		if err != nil {
+			return nil, err
+		}
+		v, err := json.Marshal(m.val)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(k)
+		buf.WriteByte(':')
+		buf.Write(v)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// toValue returns the JSON encoding of v as an object,
+// []interface{}, string, json.Number, bool, or nil.
+func toValue(v interface{}) (interface{}, error) {
+	buf, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	d := json.NewDecoder(bytes.NewReader(buf))
+	d.UseNumber()
+	return decode(d)
+}
+
+func decode(d *json.Decoder) (interface{}, error) {
+	tok, err := d.Token()
+	if err != nil {
+		return nil, err
+	}
+	switch tok {
+	case json.Delim('{'):
+		o := object{}
+		for d.More() {
+			k, err := d.Token()
+			if err != nil {
+				return nil, err
+			}
+			v, err := decode(d)
+			if err != nil {
+				return nil, err
+			}
+			o = append(o, member{key: k.(string), val: v})
+		}
+		_, err := d.Token()
+		return o, err
+	case json.Delim('['):
+		a := []interface{}{}
+		for d.More() {
+			v, err := decode(d)
+			if err != nil {
+				return nil, err
+			}
+			a = append(a, v)
+		}
+		_, err := d.Token()
+		return a, err
+	default:
+		return tok, nil
+	}
+}
+
+// sectionAliases maps alternative section names to the JSON
+// names of Info fields.
+var sectionAliases = map[string]string{
+	"cpu": "cpus",
+}
+
+// sections returns the JSON names of the Info fields, which
+// are the sections that can be selected with -only, in
+// addition to "cache".
+func sections() []string {
+	var names []string
+	t := reflect.TypeOf(sysinfo.Info{})
+	for i := 0; i < t.NumField(); i++ {
+		name := t.Field(i).Tag.Get("json")
+		if j := strings.IndexByte(name, ','); j >= 0 {
+			name = name[:j]
+		}
+		if name != "" && name != "-" && name != "schema_version" {
+			names = append(names, name)
+		}
+	}
+	return append(names, "cache")
+}
+
+// selectSections returns the named sections of v, which is
+// the encoding of Info.
+//
+// The "cache" section is the cache information of each CPU.
+func selectSections(v interface{}, names []string) (interface{}, error) {
+	info, ok := v.(object)
+	if !ok {
+		return nil, fmt.Errorf("invalid info: %T", v)
+	}
+	valid := sections()
+	var out object
+	for _, name := range names {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if alias, ok := sectionAliases[name]; ok {
+			name = alias
+		}
+		if name == "" {
+			continue
+		}
+		if _, ok := out.get(name); ok {
+			continue
+		}
+		if name == "cache" {
+			out = append(out, member{key: name, val: cacheSection(info)})
+			continue
+		}
+		if !contains(valid, name) {
+			return nil, fmt.Errorf("unknown section %q (valid sections: %s)",
+				name, strings.Join(valid, ", "))
+		}
+		// Sections that were omitted from the encoding,
+		// such as an unreadable SMBIOS table, are skipped.
+		if val, ok := info.get(name); ok {
+			out = append(out, member{key: name, val: val})
+		}
+	}
+	return out, nil
+}
+
+// cacheSection returns the processor number and cache of
+// each CPU.
+func cacheSection(info object) []interface{} {
+	cpus, _ := info.get("cpus")
+	list, _ := cpus.([]interface{})
+	caches := []interface{}{}
+	for _, c := range list {
+		c, ok := c.(object)
+		if !ok {
+			continue
+		}
+		proc, _ := c.get("processor")
+		e := object{{key: "processor", val: proc}}
+		cache, _ := c.get("cache")
+		if cache, ok := cache.(object); ok {
+			e = append(e, cache...)
+		}
+		caches = append(caches, e)
+	}
+	return caches
+}
+
+func contains(list []string, s string) bool {
+	for _, x := range list {
+		if x == s {
+			return true
+		}
+	}
+	return false
+}
+
+func formatFlag(fs *flag.FlagSet) *string {
+	return fs.String("format", "text", "output `format`: text, json, or kv")
+}
+
+// write writes v in the named format.
+func write(w io.Writer, format string, v interface{}) error {
+	bw := bufio.NewWriter(w)
+	switch format {
+	case "text":
+		writeText(bw, "", "", v)
+	case "kv":
+		writeKV(bw, "", v)
+	case "json":
+		buf, err := json.MarshalIndent(v, "", "\t")
+		if err != nil {
+			return err
+		}
+		bw.Write(buf)
+		bw.WriteByte('\n')
+	default:
+		return fmt.Errorf("unknown format %q", format)
+	}
+	return bw.Flush()
+}
+
+// writeText writes v as indented "key: value" lines.
+//
+// Lists of scalars, such as features, are written on a
+// single line and the elements of other lists are prefixed
+// with "- ". Empty strings, lists, and objects are omitted.
+func writeText(w *bufio.Writer, indent, first string, v interface{}) {
+	switch v := v.(type) {
+	case object:
+		for _, m := range v {
+			if isEmpty(m.val) {
+				continue
+			}
+			prefix := indent
+			if first != "" {
+				prefix, first = first, ""
+			}
+			if s, ok := scalar(m.val); ok {
+				fmt.Fprintf(w, "%s%s: %s\n", prefix, m.key, s)
+				continue
+			}
+			fmt.Fprintf(w, "%s%s:\n", prefix, m.key)
+			writeText(w, indent+"  ", indent+"  ", m.val)
+		}
+	case []interface{}:
+		for _, e := range v {
+			if s, ok := scalar(e); ok {
+				fmt.Fprintf(w, "%s- %s\n", indent, s)
+				continue
+			}
+			writeText(w, indent+"  ", indent+"- ", e)
+		}
+	}
+}
+
+// writeKV writes v as "path=value" lines, where path uses the
+// JSON field names, for example "cpus[0].cache.l2".
+//
+// Lists of scalars, such as features, are written on a
+// single line separated by spaces. Empty strings, lists, and
+// objects are omitted.
+func writeKV(w *bufio.Writer, path string, v interface{}) {
+	if isEmpty(v) {
+		return
+	}
+	if s, ok := scalar(v); ok {
+		fmt.Fprintf(w, "%s=%s\n", path, s)
+		return
+	}
+	switch v := v.(type) {
+	case object:
+		for _, m := range v {
+			p := m.key
+			if path != "" {
+				p = path + "." + m.key
+			}
+			writeKV(w, p, m.val)
+		}
+	case []interface{}:
+		for i, e := range v {
+			writeKV(w, fmt.Sprintf("%s[%d]", path, i), e)
+		}
+	}
+}
+
+// scalar returns v as a string if v is a scalar or a list of
+// scalars.
+func scalar(v interface{}) (string, bool) {
+	switch v := v.(type) {
+	case string:
+		return v, true
+	case json.Number:
+		return v.String(), true
+	case bool:
+		return fmt.Sprint(v), true
+	case []interface{}:
+		s := make([]string, len(v))
+		for i, e := range v {
+			switch e.(type) {
+			case string, json.Number, bool:
+				s[i], _ = scalar(e)
+			default:
+				return "", false
+			}
+		}
+		return strings.Join(s, " "), true
+	}
+	return "", false
+}
+
+// isEmpty reports whether v is null or an empty string, list,
+// or object.
+func isEmpty(v interface{}) bool {
+	switch v := v.(type) {
+	case nil:
+		return true
+	case string:
+		return v == ""
+	case []interface{}:
+		return len(v) == 0
+	case object:
+		for _, m := range v {
+			if !isEmpty(m.val) {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
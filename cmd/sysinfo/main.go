@@ -0,0 +1,450 @@
+// Command sysinfo prints information about the current host.
+//
+// Usage:
+//
+//	sysinfo [command] [flags] [args]
+//
+// The commands are:
+//
+//	show      print host information (the default)
+//	cpus      print a table of CPUs
+//	features  print CPU features
+//	lscpu     print CPU information in the format of lscpu
+//	capture   write a capture archive for offline replay
+//	serve     serve Prometheus metrics over HTTP
+//	diff      compare two snapshots
+//
+// Host information can be printed as human-readable text, as
+// JSON, or as "key=value" lines:
+//
+//	sysinfo show -format kv -only cpu,cache
+//
+// The lscpu command mimics lscpu(1) from util-linux,
+// including its -e (--extended) and -J (--json) flags, for
+// hosts that do not have it.
+//
+// The show, cpus, features, and lscpu commands accept a snapshot
+// file, either the JSON output of "sysinfo show -format json"
+// or an archive written by "sysinfo capture", instead of
+// detecting the current host.
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/ericlagergren/sysinfo"
+)
+
+func main() {
+	e := &env{
+		stdout: os.Stdout,
+		stderr: os.Stderr,
+		detect: sysinfo.Detect,
+	}
+	os.Exit(e.run(os.Args[1:]))
+}
+
+// env is the environment a command runs in.
+type env struct {
+	stdout io.Writer
+	stderr io.Writer
+	detect func() sysinfo.Info
+}
+
+type command struct {
+	name  string
+	args  string
+	short string
+	run   func(e *env, args []string) error
+}
+
+var commands []command
+
+func init() {
+	// commands is initialized here because the commands
+	// refer to it in their usage messages.
+	commands = []command{
+		{"show", "[-format text|json|kv] [-only sections] [file]", "print host information", (*env).show},
+		{"cpus", "[-format text|json|kv] [-summary] [file]", "print a table of CPUs", (*env).cpus},
+		{"features", "[-cpu list] [file]", "print CPU features", (*env).features},
+		{"lscpu", "[-e] [-J] [file]", "print CPU information in the format of lscpu", (*env).lscpu},
+		{"capture", "[-o file]", "write a capture archive for offline replay", (*env).capture},
+		{"serve", "[-addr addr]", "serve Prometheus metrics over HTTP", (*env).serve},
+		{"diff", "[-format text|json] [-freq-tolerance f] old [new]", "compare two snapshots", (*env).diff},
+	}
+}
+
+// errUsage is returned by commands after printing a usage
+// message.
+var errUsage = errors.New("usage")
+
+// errDiffers is returned by diff when the snapshots differ.
+var errDiffers = errors.New("snapshots differ")
+
+// run runs the command in args and returns the exit code.
+func (e *env) run(args []string) int {
+	name := "show"
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		name, args = args[0], args[1:]
+	}
+	if name == "help" {
+		e.usage(e.stdout)
+		return 0
+	}
+	for _, c := range commands {
+		if c.name != name {
+			continue
+		}
+		err := c.run(e, args)
+		switch {
+		case err == nil, errors.Is(err, flag.ErrHelp):
+			return 0
+		case errors.Is(err, errDiffers):
+			return 1
+		case errors.Is(err, errUsage):
+			return 2
+		default:
+			fmt.Fprintf(e.stderr, "sysinfo %s: %v\n", name, err)
+			return 2
+		}
+	}
+	fmt.Fprintf(e.stderr, "sysinfo: unknown command %q\n", name)
+	e.usage(e.stderr)
+	return 2
+}
+
+func (e *env) usage(w io.Writer) {
+	fmt.Fprintf(w, "usage: sysinfo [command] [flags] [args]\n\ncommands:\n")
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	for _, c := range commands {
+		fmt.Fprintf(tw, "\t%s\t%s\n", c.name, c.short)
+	}
+	tw.Flush()
+	fmt.Fprintf(w, "\nrun \"sysinfo <command> -h\" for the command's flags\n")
+}
+
+// flags returns the flag set for the named command.
+func (e *env) flags(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(e.stderr)
+	fs.Usage = func() {
+		for _, c := range commands {
+			if c.name == name {
+				fmt.Fprintf(e.stderr, "usage: sysinfo %s %s\n\n%s.\n\n", c.name, c.args, c.short)
+			}
+		}
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+// parse parses the command's flags and checks that it has at
+// most max arguments.
+func (e *env) parse(fs *flag.FlagSet, args []string, max int) error {
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() > max {
+		fs.Usage()
+		return errUsage
+	}
+	return nil
+}
+
+// info returns the host information from the snapshot file
+// named by the first argument, if any, or the current host.
+func (e *env) info(args []string) (sysinfo.Info, error) {
+	if len(args) > 0 {
+		return load(args[0])
+	}
+	return e.detect(), nil
+}
+
+func (e *env) show(args []string) error {
+	fs := e.flags("show")
+	format := formatFlag(fs)
+	only := fs.String("only", "", "comma-separated `sections` to print, for example cpu,cache,system")
+	if err := e.parse(fs, args, 1); err != nil {
+		return err
+	}
+	info, err := e.info(fs.Args())
+	if err != nil {
+		return err
+	}
+	v, err := toValue(info)
+	if err != nil {
+		return err
+	}
+	if *only != "" {
+		v, err = selectSections(v, strings.Split(*only, ","))
+		if err != nil {
+			return err
+		}
+	}
+	return write(e.stdout, *format, v)
+}
+
+func (e *env) cpus(args []string) error {
+	fs := e.flags("cpus")
+	format := formatFlag(fs)
+	summary := fs.Bool("summary", false, "group identical CPUs into classes")
+	if err := e.parse(fs, args, 1); err != nil {
+		return err
+	}
+	info, err := e.info(fs.Args())
+	if err != nil {
+		return err
+	}
+	if *summary {
+		s := info.Summary()
+		if *format != "text" {
+			v, err := toValue(s)
+			if err != nil {
+				return err
+			}
+			return write(e.stdout, *format, v)
+		}
+		for _, c := range s.Classes {
+			fmt.Fprintln(e.stdout, c)
+		}
+		if n := s.Offline.Len(); n > 0 {
+			fmt.Fprintf(e.stdout, "offline ×%d (%s)\n", n, s.Offline)
+		}
+		return nil
+	}
+	if *format != "text" {
+		v, err := toValue(info)
+		if err != nil {
+			return err
+		}
+		v, err = selectSections(v, []string{"cpus"})
+		if err != nil {
+			return err
+		}
+		return write(e.stdout, *format, v)
+	}
+	tw := tabwriter.NewWriter(e.stdout, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(tw, "CPU\tONLINE\tSOCKET\tCORE\tMHZ\tMODEL")
+	for _, c := range info.CPUs {
+		if !c.Online {
+			// Offline CPUs are not described.
+			fmt.Fprintf(tw, "%d\tno\t-\t-\t-\t-\n", c.Proc)
+			continue
+		}
+		mhz := "-"
+		if c.Freq != 0 {
+			mhz = fmt.Sprintf("%.0f", c.Freq)
+		}
+		fmt.Fprintf(tw, "%d\t%s\t%d\t%d\t%s\t%s\n",
+			c.Proc, yesNo(c.Online), c.PhysID, c.CoreID, mhz, cpuModel(c))
+	}
+	return tw.Flush()
+}
+
+// cpuModel returns a short description of the CPU's model.
+func cpuModel(c sysinfo.CPU) string {
+	switch {
+	case c.Part != 0 && c.Impl != 0:
+		return fmt.Sprintf("%s %s", c.Impl, c.Part)
+	case c.ModelName != "":
+		return c.ModelName
+	case c.MicroArch != "":
+		return c.MicroArch
+	default:
+		return "-"
+	}
+}
+
+func yesNo(b bool) string {
+	if b {
+		return "yes"
+	}
+	return "no"
+}
+
+func (e *env) features(args []string) error {
+	fs := e.flags("features")
+	list := fs.String("cpu", "", "only print the features of the CPUs in `list`, for example 0-3,8")
+	if err := e.parse(fs, args, 1); err != nil {
+		return err
+	}
+	var only sysinfo.CPUSet
+	if *list != "" {
+		var err error
+		only, err = sysinfo.ParseCPUList(*list)
+		if err != nil {
+			return err
+		}
+	}
+	info, err := e.info(fs.Args())
+	if err != nil {
+		return err
+	}
+
+	var (
+		all   sysinfo.CPUSet
+		names []string
+		sets  = make(map[string]*sysinfo.CPUSet)
+	)
+	for _, c := range info.CPUs {
+		// Offline CPUs are not described.
+		if !c.Online || (*list != "" && !only.Has(c.Proc)) {
+			continue
+		}
+		all.Set(c.Proc)
+		for _, f := range c.Features {
+			set, ok := sets[f]
+			if !ok {
+				set = new(sysinfo.CPUSet)
+				sets[f] = set
+				names = append(names, f)
+			}
+			set.Set(c.Proc)
+		}
+	}
+	sort.Strings(names)
+
+	// Features that only some of the CPUs support, which is
+	// common on heterogeneous Arm systems, are annotated
+	// with the CPUs that support them.
+	tw := tabwriter.NewWriter(e.stdout, 0, 8, 2, ' ', 0)
+	for _, f := range names {
+		if set := sets[f]; !set.Equal(all) {
+			fmt.Fprintf(tw, "%s\tcpus %s\n", f, set)
+		} else {
+			fmt.Fprintln(tw, f)
+		}
+	}
+	return tw.Flush()
+}
+
+func (e *env) lscpu(args []string) error {
+	fs := e.flags("lscpu")
+	var opts sysinfo.LSCPUOptions
+	fs.BoolVar(&opts.Extended, "e", false, "print a table with a row per CPU")
+	fs.BoolVar(&opts.Extended, "extended", false, "same as -e")
+	fs.BoolVar(&opts.JSON, "J", false, "print JSON")
+	fs.BoolVar(&opts.JSON, "json", false, "same as -J")
+	if err := e.parse(fs, args, 1); err != nil {
+		return err
+	}
+	info, err := e.info(fs.Args())
+	if err != nil {
+		return err
+	}
+	return info.WriteLSCPU(e.stdout, opts)
+}
+
+func (e *env) capture(args []string) error {
+	fs := e.flags("capture")
+	out := fs.String("o", "", "write the archive to `file` instead of standard output")
+	if err := e.parse(fs, args, 0); err != nil {
+		return err
+	}
+	if *out == "" {
+		return sysinfo.Capture(e.stdout)
+	}
+	f, err := os.Create(*out)
+	if err != nil {
+		return err
+	}
+	if err := sysinfo.Capture(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func (e *env) serve(args []string) error {
+	fs := e.flags("serve")
+	addr := fs.String("addr", ":9110", "listen on `addr`")
+	if err := e.parse(fs, args, 0); err != nil {
+		return err
+	}
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", sysinfo.Handler())
+	fmt.Fprintf(e.stderr, "serving metrics on %s/metrics\n", *addr)
+	return http.ListenAndServe(*addr, mux)
+}
+
+func (e *env) diff(args []string) error {
+	fs := e.flags("diff")
+	format := fs.String("format", "text", "output `format`: text or json")
+	tol := fs.Float64("freq-tolerance", sysinfo.DefaultFreqTolerance,
+		"relative change in CPU frequency below which it is not reported, or 0 to report every change")
+	if err := e.parse(fs, args, 2); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return errUsage
+	}
+	a, err := load(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	b, err := e.info(fs.Args()[1:])
+	if err != nil {
+		return err
+	}
+	opts := sysinfo.DiffOptions{FreqTolerance: *tol}
+	if *tol == 0 {
+		opts.FreqTolerance = -1
+	}
+	changes := opts.Diff(a, b)
+
+	switch *format {
+	case "text":
+		for _, c := range changes {
+			fmt.Fprintln(e.stdout, c)
+		}
+	case "json":
+		if changes == nil {
+			changes = []sysinfo.Change{}
+		}
+		buf, err := json.MarshalIndent(changes, "", "\t")
+		if err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintf(e.stdout, "%s\n", buf); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unknown format %q", *format)
+	}
+	if len(changes) > 0 {
+		return errDiffers
+	}
+	return nil
+}
+
+// load reads a snapshot file, which is either JSON-encoded
+// Info or a capture archive.
+func load(name string) (sysinfo.Info, error) {
+	buf, err := os.ReadFile(name)
+	if err != nil {
+		return sysinfo.Info{}, err
+	}
+	if bytes.HasPrefix(buf, []byte("PK\x03\x04")) {
+		return sysinfo.DetectFromArchive(bytes.NewReader(buf))
+	}
+	var info sysinfo.Info
+	if err := json.Unmarshal(buf, &info); err != nil {
+		return sysinfo.Info{}, fmt.Errorf("%s: %w", name, err)
+	}
+	if info.SchemaVersion != sysinfo.SchemaVersion {
+		return sysinfo.Info{}, fmt.Errorf("%s: unsupported schema version %d (want %d)",
+			name, info.SchemaVersion, sysinfo.SchemaVersion)
+	}
+	return info, nil
+}
@@ -0,0 +1,200 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ericlagergren/sysinfo"
+)
+
+func testInfo() sysinfo.Info {
+	feats := []string{"fp", "asimd", "aes"}
+	return sysinfo.Info{
+		SchemaVersion: sysinfo.SchemaVersion,
+		CPUs: []sysinfo.CPU{
+			{Proc: 0, Online: true, Features: feats, Impl: sysinfo.ARMLtd, Part: sysinfo.CortexA53,
+				Cache: sysinfo.Cache{L1: 32 << 10, L2: 512 << 10}},
+			{Proc: 1, Online: true, Features: append(feats, "asimddp"), Impl: sysinfo.ARMLtd, Part: sysinfo.CortexA72,
+				Cache: sysinfo.Cache{L1: 48 << 10, L2: 1 << 20}},
+		},
+		NUMA: []sysinfo.NUMANode{
+			{ID: 0, CPUs: sysinfo.NewCPUSet(0, 1), MemTotal: 4 << 30},
+		},
+		System: sysinfo.System{Vendor: "Pine64", Product: "RockPro64"},
+	}
+}
+
+func runTest(t *testing.T, info sysinfo.Info, args ...string) (stdout, stderr string, code int) {
+	t.Helper()
+	var out, errs bytes.Buffer
+	e := &env{
+		stdout: &out,
+		stderr: &errs,
+		detect: func() sysinfo.Info { return info },
+	}
+	code = e.run(args)
+	return out.String(), errs.String(), code
+}
+
+func TestShow(t *testing.T) {
+	for _, tc := range []struct {
+		args []string
+		want string
+	}{
+		{
+			args: []string{"-only", "system,numa"},
+			want: `system:
+  vendor: Pine64
+  product: RockPro64
+numa:
+  - id: 0
+    cpus: 0-1
+    mem_total: 4294967296
+    mem_free: 0
+`,
+		},
+		{
+			args: []string{"show", "-format", "kv", "-only", "cache"},
+			want: `cache[0].processor=0
+cache[0].l1=32768
+cache[0].l2=524288
+cache[1].processor=1
+cache[1].l1=49152
+cache[1].l2=1048576
+`,
+		},
+		{
+			args: []string{"show", "-format=json", "--only=cpu"},
+			want: "",
+		},
+	} {
+		out, errs, code := runTest(t, testInfo(), tc.args...)
+		if code != 0 {
+			t.Fatalf("%q: exit code %d: %s", tc.args, code, errs)
+		}
+		if tc.want == "" {
+			var v struct {
+				CPUs []sysinfo.CPU `json:"cpus"`
+			}
+			if err := json.Unmarshal([]byte(out), &v); err != nil {
+				t.Fatalf("%q: %v", tc.args, err)
+			}
+			if len(v.CPUs) != 2 || v.CPUs[1].Part != sysinfo.CortexA72 {
+				t.Fatalf("%q: unexpected output: %s", tc.args, out)
+			}
+			continue
+		}
+		if out != tc.want {
+			t.Fatalf("%q: expected\n%s\ngot\n%s", tc.args, tc.want, out)
+		}
+	}
+
+	_, errs, code := runTest(t, testInfo(), "-only", "bogus")
+	if code != 2 || !strings.Contains(errs, `unknown section "bogus"`) {
+		t.Fatalf("expected unknown section error, got %d: %s", code, errs)
+	}
+}
+
+func TestFeatures(t *testing.T) {
+	out, errs, code := runTest(t, testInfo(), "features")
+	if code != 0 {
+		t.Fatalf("exit code %d: %s", code, errs)
+	}
+	const want = `aes
+asimd
+asimddp  cpus 1
+fp
+`
+	if out != want {
+		t.Fatalf("expected\n%s\ngot\n%s", want, out)
+	}
+
+	out, _, _ = runTest(t, testInfo(), "features", "-cpu", "1")
+	if !strings.Contains(out, "asimddp\n") {
+		t.Fatalf("expected asimddp without annotation, got\n%s", out)
+	}
+
+	// Offline CPUs have no features, but do not make the
+	// features look partial.
+	info := testInfo()
+	info.CPUs = append(info.CPUs, sysinfo.CPU{Proc: 2})
+	out, _, _ = runTest(t, info, "features")
+	if out != want {
+		t.Fatalf("expected\n%s\ngot\n%s", want, out)
+	}
+}
+
+func TestCPUs(t *testing.T) {
+	info := testInfo()
+	info.CPUs = append(info.CPUs, sysinfo.CPU{Proc: 2})
+	out, errs, code := runTest(t, info, "cpus")
+	if code != 0 {
+		t.Fatalf("exit code %d: %s", code, errs)
+	}
+	const want = `CPU  ONLINE  SOCKET  CORE  MHZ  MODEL
+0    yes     0       0     -    ARM Ltd Cortex-A53 (0xd03)
+1    yes     0       0     -    ARM Ltd Cortex-A72 (0xd08)
+2    no      -       -     -    -
+`
+	if out != want {
+		t.Fatalf("expected\n%s\ngot\n%s", want, out)
+	}
+}
+
+func TestCPUsSummary(t *testing.T) {
+	out, errs, code := runTest(t, testInfo(), "cpus", "-summary")
+	if code != 0 {
+		t.Fatalf("exit code %d: %s", code, errs)
+	}
+	const want = "Cortex-A53 ×1 (0)\nCortex-A72 ×1 (1)\n"
+	if out != want {
+		t.Fatalf("expected %q, got %q", want, out)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	dir := t.TempDir()
+	old := filepath.Join(dir, "old.json")
+	out, errs, code := runTest(t, testInfo(), "show", "-format", "json")
+	if code != 0 {
+		t.Fatalf("exit code %d: %s", code, errs)
+	}
+	if err := os.WriteFile(old, []byte(out), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	_, errs, code = runTest(t, testInfo(), "diff", old)
+	if code != 0 {
+		t.Fatalf("expected no differences, got exit code %d: %s", code, errs)
+	}
+
+	info := testInfo()
+	info.CPUs[0].Features = []string{"fp", "asimd", "aes", "crc32"}
+	out, _, code = runTest(t, info, "diff", old)
+	if code != 1 {
+		t.Fatalf("expected exit code 1, got %d", code)
+	}
+	const want = "+ cpus[0].features: crc32\n"
+	if out != want {
+		t.Fatalf("expected %q, got %q", want, out)
+	}
+
+	if err := os.WriteFile(old, []byte(`{"schema_version": 0}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	_, errs, code = runTest(t, info, "diff", old)
+	if code != 2 || !strings.Contains(errs, "unsupported schema version") {
+		t.Fatalf("expected schema version error, got %d: %s", code, errs)
+	}
+}
+
+func TestUnknownCommand(t *testing.T) {
+	_, errs, code := runTest(t, testInfo(), "bogus")
+	if code != 2 || !strings.Contains(errs, `unknown command "bogus"`) {
+		t.Fatalf("expected unknown command error, got %d: %s", code, errs)
+	}
+}
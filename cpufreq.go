@@ -0,0 +1,18 @@
+package sysinfo
+
+import (
+	"fmt"
+	"io/fs"
+)
+
+// readGovernors reads the cpufreq scaling governor of each
+// CPU in o.
+//
+// fsys should be rooted at "/".
+func readGovernors(fsys fs.FS, o *Info) {
+	for i := range o.CPUs {
+		c := &o.CPUs[i]
+		c.Governor = readString(fsys, fmt.Sprintf(
+			"sys/devices/system/cpu/cpu%d/cpufreq/scaling_governor", c.Proc))
+	}
+}
@@ -0,0 +1,122 @@
+package sysinfo
+
+import (
+	"fmt"
+	"io/fs"
+	"path"
+)
+
+// CPUIdle describes the kernel's CPU idle (C-state)
+// management.
+//
+// On Linux, this information is read from
+// /sys/devices/system/cpu/cpuidle.
+type CPUIdle struct {
+	// Driver is the cpuidle driver, for example "intel_idle",
+	// "acpi_idle", or "psci_idle".
+	//
+	// Matches: current_driver
+	Driver string `json:"driver,omitempty"`
+	// Governor is the cpuidle governor, for example "menu"
+	// or "teo".
+	//
+	// Matches: current_governor, current_governor_ro
+	Governor string `json:"governor,omitempty"`
+	// Deepest is the name of the deepest idle state that is
+	// enabled on any CPU.
+	Deepest string `json:"deepest,omitempty"`
+	// DeepestLatency is the exit latency in microseconds of
+	// the Deepest idle state.
+	DeepestLatency int `json:"deepest_latency_us,omitempty"`
+}
+
+// IdleState is a CPU idle state (C-state).
+//
+// Each field has a "Matches:" comment describing the file in
+// /sys/devices/system/cpu/cpuN/cpuidle/stateM used.
+type IdleState struct {
+	// Name is the state's name, for example "C1E".
+	//
+	// Matches: name
+	Name string `json:"name"`
+	// Desc is the state's description, for example "MWAIT
+	// 0x01".
+	//
+	// Matches: desc
+	Desc string `json:"desc,omitempty"`
+	// Latency is the exit latency in microseconds.
+	//
+	// Matches: latency
+	Latency int `json:"latency_us"`
+	// Residency is the target residency in microseconds, the
+	// minimum time the CPU must stay in the state for
+	// entering it to be worthwhile.
+	//
+	// Matches: residency
+	Residency int `json:"residency_us"`
+	// Disabled is whether the state has been disabled.
+	//
+	// Matches: disable
+	Disabled bool `json:"disabled,omitempty"`
+	// Usage is the number of times the state was entered.
+	//
+	// Matches: usage
+	Usage uint64 `json:"usage"`
+	// Time is the total time in microseconds spent in the
+	// state.
+	//
+	// Matches: time
+	Time uint64 `json:"time_us"`
+}
+
+// DeepestIdle returns the CPU's deepest enabled idle state.
+func (c CPU) DeepestIdle() (IdleState, bool) {
+	for i := len(c.Idle) - 1; i >= 0; i-- {
+		if !c.Idle[i].Disabled {
+			return c.Idle[i], true
+		}
+	}
+	return IdleState{}, false
+}
+
+// readCPUIdle reads the idle states of each CPU in o and
+// summarizes them in o.CPUIdle.
+//
+// fsys should be rooted at "/".
+func readCPUIdle(fsys fs.FS, o *Info) {
+	const dir = "sys/devices/system/cpu/cpuidle/"
+	s := &o.CPUIdle
+	s.Driver = readString(fsys, dir+"current_driver")
+	s.Governor = readString(fsys, dir+"current_governor")
+	if s.Governor == "" {
+		s.Governor = readString(fsys, dir+"current_governor_ro")
+	}
+	for i := range o.CPUs {
+		c := &o.CPUs[i]
+		c.Idle = readIdleStates(fsys, c.Proc)
+		if d, ok := c.DeepestIdle(); ok && (s.Deepest == "" || d.Latency > s.DeepestLatency) {
+			s.Deepest = d.Name
+			s.DeepestLatency = d.Latency
+		}
+	}
+}
+
+// readIdleStates reads the idle states of a single CPU.
+func readIdleStates(fsys fs.FS, cpu int) []IdleState {
+	dir := fmt.Sprintf("sys/devices/system/cpu/cpu%d/cpuidle", cpu)
+	var list []IdleState
+	for _, name := range numberedDirs(fsys, dir, "state") {
+		state := path.Join(dir, name)
+		s := IdleState{
+			Name:      readString(fsys, state+"/name"),
+			Desc:      readString(fsys, state+"/desc"),
+			Latency:   atoi(readString(fsys, state+"/latency")),
+			Residency: atoi(readString(fsys, state+"/residency")),
+			Disabled:  readString(fsys, state+"/disable") == "1",
+		}
+		s.Usage, _ = readUint(fsys, state+"/usage")
+		s.Time, _ = readUint(fsys, state+"/time")
+		list = append(list, s)
+	}
+	return list
+}
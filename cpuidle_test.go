@@ -0,0 +1,73 @@
+package sysinfo
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"testing/fstest"
+)
+
+func TestReadCPUIdle(t *testing.T) {
+	file := func(s string) *fstest.MapFile {
+		return &fstest.MapFile{Data: []byte(s + "\n")}
+	}
+	fsys := fstest.MapFS{
+		"sys/devices/system/cpu/cpuidle/current_driver":      file("intel_idle"),
+		"sys/devices/system/cpu/cpuidle/current_governor_ro": file("menu"),
+	}
+	states := []struct {
+		name, desc, latency, residency, usage, time string
+	}{
+		{"POLL", "CPUIDLE CORE POLL IDLE", "0", "0", "3528", "27315"},
+		{"C1", "MWAIT 0x00", "2", "2", "52118", "12045128"},
+		{"C1E", "MWAIT 0x01", "10", "20", "161829", "70117365"},
+		{"C6", "MWAIT 0x20", "133", "600", "824871", "4521578135"},
+	}
+	for cpu := 0; cpu < 2; cpu++ {
+		for i, s := range states {
+			dir := fmt.Sprintf("sys/devices/system/cpu/cpu%d/cpuidle/state%d/", cpu, i)
+			fsys[dir+"name"] = file(s.name)
+			fsys[dir+"desc"] = file(s.desc)
+			fsys[dir+"latency"] = file(s.latency)
+			fsys[dir+"residency"] = file(s.residency)
+			fsys[dir+"usage"] = file(s.usage)
+			fsys[dir+"time"] = file(s.time)
+			fsys[dir+"disable"] = file("0")
+		}
+	}
+	// C6 is disabled on CPU 1.
+	fsys["sys/devices/system/cpu/cpu1/cpuidle/state3/disable"] = file("1")
+
+	v := Info{CPUs: []CPU{{Proc: 0}, {Proc: 1}}}
+	readCPUIdle(fsys, &v)
+
+	want := CPUIdle{
+		Driver:         "intel_idle",
+		Governor:       "menu",
+		Deepest:        "C6",
+		DeepestLatency: 133,
+	}
+	if v.CPUIdle != want {
+		t.Fatalf("expected %#v, got %#v", want, v.CPUIdle)
+	}
+
+	wantStates := []IdleState{
+		{Name: "POLL", Desc: "CPUIDLE CORE POLL IDLE", Usage: 3528, Time: 27315},
+		{Name: "C1", Desc: "MWAIT 0x00", Latency: 2, Residency: 2, Usage: 52118, Time: 12045128},
+		{Name: "C1E", Desc: "MWAIT 0x01", Latency: 10, Residency: 20, Usage: 161829, Time: 70117365},
+		{Name: "C6", Desc: "MWAIT 0x20", Latency: 133, Residency: 600, Usage: 824871, Time: 4521578135},
+	}
+	if !reflect.DeepEqual(v.CPUs[0].Idle, wantStates) {
+		t.Fatalf("expected %#v, got %#v", wantStates, v.CPUs[0].Idle)
+	}
+
+	if d, ok := v.CPUs[0].DeepestIdle(); !ok || d.Name != "C6" {
+		t.Fatalf("unexpected deepest state: %#v", d)
+	}
+	if d, ok := v.CPUs[1].DeepestIdle(); !ok || d.Name != "C1E" {
+		t.Fatalf("unexpected deepest state: %#v", d)
+	}
+	if _, ok := (CPU{}).DeepestIdle(); ok {
+		t.Fatal("expected no idle states")
+	}
+}
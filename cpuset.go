@@ -0,0 +1,416 @@
+package sysinfo
+
+import (
+	"bytes"
+	"encoding"
+	"fmt"
+	"math"
+	"math/bits"
+	"strconv"
+	"strings"
+)
+
+// CPUSet is a set of CPU numbers.
+//
+// Its text form is the kernel's "cpulist" syntax, for example
+// "0-3,8-11". The zero value is an empty set.
+//
+// Like a slice, copying a CPUSet does not copy its contents.
+// Use Clone to make an independent copy.
+type CPUSet struct {
+	w []uint64
+}
+
+var (
+	_ encoding.TextMarshaler   = CPUSet{}
+	_ encoding.TextUnmarshaler = (*CPUSet)(nil)
+	_ fmt.Stringer             = CPUSet{}
+)
+
+// maxCPUs is one more than the largest CPU number that is
+// parsed, so that malformed input cannot allocate a huge set.
+// It is larger than the kernel's largest NR_CPUS.
+const maxCPUs = 1 << 16
+
+// NewCPUSet returns a set containing the provided CPUs.
+func NewCPUSet(cpus ...int) CPUSet {
+	var s CPUSet
+	for _, c := range cpus {
+		s.Set(c)
+	}
+	return s
+}
+
+// ParseCPUList parses a CPU set in the kernel's "cpulist"
+// syntax, as found in /sys/devices/system/cpu/online,
+// shared_cpu_list, cpuset.cpus, and so on.
+//
+// It should look like
+//
+//	0-3,8-11,16
+//
+// Ranges may also use the strided form "0-15:2/4", which
+// selects the first two CPUs of every group of four. An empty
+// string is an empty set. CPU numbers must be less than
+// 65536.
+func ParseCPUList(s string) (CPUSet, error) {
+	var set CPUSet
+	if !set.parseList([]byte(s)) {
+		return CPUSet{}, fmt.Errorf("sysinfo: invalid cpulist %q", s)
+	}
+	return set, nil
+}
+
+// parseList parses a cpulist into s, reusing its storage.
+//
+// It does not allocate unless s needs to grow.
+func (s *CPUSet) parseList(b []byte) bool {
+	s.w = s.w[:0]
+	b = bytes.TrimSpace(b)
+	for len(b) > 0 {
+		r := b
+		if i := bytes.IndexByte(b, ','); i >= 0 {
+			r, b = b[:i], b[i+1:]
+			if len(b) == 0 {
+				return false
+			}
+		} else {
+			b = nil
+		}
+		used, group := 1, 1
+		if i := bytes.IndexByte(r, ':'); i >= 0 {
+			var ok bool
+			used, group, ok = parseStride(r[i+1:])
+			if !ok {
+				return false
+			}
+			r = r[:i]
+		}
+		lo, hi := r, r
+		if i := bytes.IndexByte(r, '-'); i >= 0 {
+			lo, hi = r[:i], r[i+1:]
+		}
+		x, ok := atoiBytes(lo)
+		if !ok {
+			return false
+		}
+		y, ok := atoiBytes(hi)
+		if !ok || y < x {
+			return false
+		}
+		for i := x; i <= y; i++ {
+			if (i-x)%group < used {
+				s.Set(i)
+			}
+		}
+	}
+	return true
+}
+
+// parseStride parses the "used/group" suffix of a strided
+// cpulist range.
+func parseStride(b []byte) (used, group int, ok bool) {
+	i := bytes.IndexByte(b, '/')
+	if i < 0 {
+		return 0, 0, false
+	}
+	used, ok = atoiBytes(b[:i])
+	if !ok || used <= 0 {
+		return 0, 0, false
+	}
+	group, ok = atoiBytes(b[i+1:])
+	if !ok || group < used {
+		return 0, 0, false
+	}
+	return used, group, true
+}
+
+// atoiBytes parses a CPU number, or another non-negative
+// decimal integer less than maxCPUs, without allocating.
+func atoiBytes(b []byte) (int, bool) {
+	x, ok := parseUintBytes(b)
+	if !ok || x >= maxCPUs {
+		return 0, false
+	}
+	return int(x), true
+}
+
+// parseUintBytes parses an unsigned decimal integer without
+// allocating.
+func parseUintBytes(b []byte) (uint64, bool) {
+	if len(b) == 0 {
+		return 0, false
+	}
+	var x uint64
+	for _, c := range b {
+		if c < '0' || c > '9' {
+			return 0, false
+		}
+		d := uint64(c - '0')
+		if x > (math.MaxUint64-d)/10 {
+			return 0, false
+		}
+		x = x*10 + d
+	}
+	return x, true
+}
+
+// ParseCPUMask parses a CPU set in the kernel's hexadecimal
+// "cpumask" syntax, as found in /proc/irq/*/smp_affinity,
+// shared_cpu_map, and so on.
+//
+// It should look like
+//
+//	00000000,0000ff0f
+//
+// where each comma-separated word holds 32 CPUs and the
+// most significant word is first. CPU numbers must be less
+// than 65536.
+func ParseCPUMask(s string) (CPUSet, error) {
+	var set CPUSet
+	in := s
+	s = strings.TrimSpace(s)
+	s = strings.ReplaceAll(s, ",", "")
+	s = strings.TrimPrefix(s, "0x")
+	if s == "" {
+		return set, nil
+	}
+	for i := 0; i < len(s); i++ {
+		d, ok := unhex(s[len(s)-1-i])
+		if !ok || (d != 0 && i >= maxCPUs/4) {
+			return CPUSet{}, fmt.Errorf("sysinfo: invalid cpumask %q", in)
+		}
+		for j := 0; j < 4; j++ {
+			if d&(1<<j) != 0 {
+				set.Set(i*4 + j)
+			}
+		}
+	}
+	return set, nil
+}
+
+func unhex(c byte) (byte, bool) {
+	switch {
+	case '0' <= c && c <= '9':
+		return c - '0', true
+	case 'a' <= c && c <= 'f':
+		return c - 'a' + 10, true
+	case 'A' <= c && c <= 'F':
+		return c - 'A' + 10, true
+	default:
+		return 0, false
+	}
+}
+
+// Set adds cpu to the set.
+func (s *CPUSet) Set(cpu int) {
+	if cpu < 0 {
+		return
+	}
+	i := cpu / 64
+	for len(s.w) <= i {
+		s.w = append(s.w, 0)
+	}
+	s.w[i] |= 1 << (cpu % 64)
+}
+
+// Clear removes cpu from the set.
+func (s *CPUSet) Clear(cpu int) {
+	if cpu < 0 || cpu/64 >= len(s.w) {
+		return
+	}
+	s.w[cpu/64] &^= 1 << (cpu % 64)
+}
+
+// Has reports whether cpu is in the set.
+func (s CPUSet) Has(cpu int) bool {
+	if cpu < 0 || cpu/64 >= len(s.w) {
+		return false
+	}
+	return s.w[cpu/64]&(1<<(cpu%64)) != 0
+}
+
+// Len returns the number of CPUs in the set.
+func (s CPUSet) Len() int {
+	n := 0
+	for _, w := range s.w {
+		n += bits.OnesCount64(w)
+	}
+	return n
+}
+
+// IsEmpty reports whether the set is empty.
+func (s CPUSet) IsEmpty() bool {
+	for _, w := range s.w {
+		if w != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Max returns the largest CPU in the set, or -1 if the set is
+// empty.
+func (s CPUSet) Max() int {
+	for i := len(s.w) - 1; i >= 0; i-- {
+		if s.w[i] != 0 {
+			return i*64 + 63 - bits.LeadingZeros64(s.w[i])
+		}
+	}
+	return -1
+}
+
+// Range calls fn for each CPU in the set in ascending order.
+//
+// If fn returns false, Range stops the iteration.
+func (s CPUSet) Range(fn func(cpu int) bool) {
+	for i, w := range s.w {
+		for w != 0 {
+			j := bits.TrailingZeros64(w)
+			if !fn(i*64 + j) {
+				return
+			}
+			w &^= 1 << j
+		}
+	}
+}
+
+// CPUs returns the CPUs in the set in ascending order.
+func (s CPUSet) CPUs() []int {
+	var list []int
+	s.Range(func(cpu int) bool {
+		list = append(list, cpu)
+		return true
+	})
+	return list
+}
+
+// Clone returns a copy of the set.
+func (s CPUSet) Clone() CPUSet {
+	if s.w == nil {
+		return CPUSet{}
+	}
+	return CPUSet{w: append([]uint64(nil), s.w...)}
+}
+
+// Equal reports whether s and t contain the same CPUs.
+func (s CPUSet) Equal(t CPUSet) bool {
+	for i := 0; i < len(s.w) || i < len(t.w); i++ {
+		if s.word(i) != t.word(i) {
+			return false
+		}
+	}
+	return true
+}
+
+// Union returns the CPUs in either s or t.
+func (s CPUSet) Union(t CPUSet) CPUSet {
+	return s.combine(t, func(x, y uint64) uint64 { return x | y })
+}
+
+// Intersect returns the CPUs in both s and t.
+func (s CPUSet) Intersect(t CPUSet) CPUSet {
+	return s.combine(t, func(x, y uint64) uint64 { return x & y })
+}
+
+// Difference returns the CPUs in s but not in t.
+func (s CPUSet) Difference(t CPUSet) CPUSet {
+	return s.combine(t, func(x, y uint64) uint64 { return x &^ y })
+}
+
+// IsSubset reports whether every CPU in s is also in t.
+func (s CPUSet) IsSubset(t CPUSet) bool {
+	return s.Difference(t).IsEmpty()
+}
+
+func (s CPUSet) combine(t CPUSet, op func(x, y uint64) uint64) CPUSet {
+	n := len(s.w)
+	if len(t.w) > n {
+		n = len(t.w)
+	}
+	var r CPUSet
+	for i := 0; i < n; i++ {
+		w := op(s.word(i), t.word(i))
+		if w != 0 {
+			for len(r.w) <= i {
+				r.w = append(r.w, 0)
+			}
+			r.w[i] = w
+		}
+	}
+	return r
+}
+
+func (s CPUSet) word(i int) uint64 {
+	if i < len(s.w) {
+		return s.w[i]
+	}
+	return 0
+}
+
+// String returns the set in the kernel's "cpulist" syntax.
+func (s CPUSet) String() string {
+	var b strings.Builder
+	lo, hi := -1, -1
+	flush := func() {
+		if lo < 0 {
+			return
+		}
+		if b.Len() > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(strconv.Itoa(lo))
+		if hi > lo {
+			b.WriteByte('-')
+			b.WriteString(strconv.Itoa(hi))
+		}
+	}
+	s.Range(func(cpu int) bool {
+		if cpu == hi+1 && lo >= 0 {
+			hi = cpu
+			return true
+		}
+		flush()
+		lo, hi = cpu, cpu
+		return true
+	})
+	flush()
+	return b.String()
+}
+
+// Mask returns the set in the kernel's hexadecimal "cpumask"
+// syntax.
+func (s CPUSet) Mask() string {
+	n := (s.Max() + 32) / 32
+	if n == 0 {
+		n = 1
+	}
+	var b strings.Builder
+	for i := n - 1; i >= 0; i-- {
+		w := uint32(s.word(i/2) >> (32 * (i % 2)))
+		fmt.Fprintf(&b, "%08x", w)
+		if i > 0 {
+			b.WriteByte(',')
+		}
+	}
+	return b.String()
+}
+
+// MarshalText implements encoding.TextMarshaler.
+//
+// The set is encoded in the kernel's "cpulist" syntax.
+func (s CPUSet) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+//
+// It accepts the kernel's "cpulist" syntax.
+func (s *CPUSet) UnmarshalText(text []byte) error {
+	v, err := ParseCPUList(string(text))
+	if err != nil {
+		return err
+	}
+	*s = v
+	return nil
+}
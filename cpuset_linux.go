@@ -0,0 +1,28 @@
+package sysinfo
+
+import "golang.org/x/sys/unix"
+
+// CPUSetFromUnix converts a unix.CPUSet to a CPUSet.
+func CPUSetFromUnix(u *unix.CPUSet) CPUSet {
+	var s CPUSet
+	n := u.Count()
+	for i := 0; n > 0; i++ {
+		if u.IsSet(i) {
+			s.Set(i)
+			n--
+		}
+	}
+	return s
+}
+
+// Unix converts the set to a unix.CPUSet.
+//
+// CPUs that do not fit in a unix.CPUSet are ignored.
+func (s CPUSet) Unix() unix.CPUSet {
+	var u unix.CPUSet
+	s.Range(func(cpu int) bool {
+		u.Set(cpu)
+		return true
+	})
+	return u
+}
@@ -0,0 +1,15 @@
+package sysinfo
+
+import "testing"
+
+func TestCPUSetUnix(t *testing.T) {
+	want := NewCPUSet(0, 5, 63, 64, 1000)
+	u := want.Unix()
+	if u.Count() != want.Len() {
+		t.Fatalf("expected %d, got %d", want.Len(), u.Count())
+	}
+	got := CPUSetFromUnix(&u)
+	if !got.Equal(want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+}
@@ -0,0 +1,147 @@
+package sysinfo
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseCPUList(t *testing.T) {
+	for _, tc := range []struct {
+		in   string
+		want []int
+		ok   bool
+	}{
+		{"", nil, true},
+		{"\n", nil, true},
+		{"0", []int{0}, true},
+		{"0-3\n", []int{0, 1, 2, 3}, true},
+		{"0-1,8-9,4", []int{0, 1, 4, 8, 9}, true},
+		{"0-15:2/4", []int{0, 1, 4, 5, 8, 9, 12, 13}, true},
+		{"64,127-128", []int{64, 127, 128}, true},
+		{"3-1", nil, false},
+		{"a-b", nil, false},
+		{"-1", nil, false},
+		{"0-7:3/2", nil, false},
+		{"65535", []int{65535}, true},
+		{"65536", nil, false},
+		{"0-200000000", nil, false},
+		{"0-2147483647", nil, false},
+		{"0-65535:1/100000", nil, false},
+	} {
+		got, err := ParseCPUList(tc.in)
+		if (err == nil) != tc.ok {
+			t.Fatalf("%q: unexpected error: %v", tc.in, err)
+		}
+		if !reflect.DeepEqual(got.CPUs(), tc.want) {
+			t.Fatalf("%q: expected %v, got %v", tc.in, tc.want, got.CPUs())
+		}
+	}
+}
+
+func TestParseCPUMaskInvalid(t *testing.T) {
+	for _, in := range []string{"0000000g,00000000", "1" + strings.Repeat("0", maxCPUs/4)} {
+		_, err := ParseCPUMask(in)
+		if err == nil {
+			t.Fatalf("%.20q: expected an error", in)
+		}
+		if in == "0000000g,00000000" && !strings.Contains(err.Error(), in) {
+			t.Fatalf("expected the error to quote the input, got %v", err)
+		}
+	}
+	// Leading zeros are fine.
+	m, err := ParseCPUMask(strings.Repeat("0", maxCPUs/4) + "1")
+	if err != nil || m.String() != "0" {
+		t.Fatalf("unexpected result: %v, %v", m, err)
+	}
+}
+
+func TestCPUSetString(t *testing.T) {
+	for _, tc := range []struct {
+		cpus []int
+		list string
+		mask string
+	}{
+		{nil, "", "00000000"},
+		{[]int{0}, "0", "00000001"},
+		{[]int{0, 1, 2, 3, 8, 9, 10, 11}, "0-3,8-11", "00000f0f"},
+		{[]int{1, 3, 5}, "1,3,5", "0000002a"},
+		{[]int{0, 32, 33, 63, 64}, "0,32-33,63-64", "00000001,80000003,00000001"},
+	} {
+		s := NewCPUSet(tc.cpus...)
+		if got := s.String(); got != tc.list {
+			t.Fatalf("%v: expected %q, got %q", tc.cpus, tc.list, got)
+		}
+		if got := s.Mask(); got != tc.mask {
+			t.Fatalf("%v: expected %q, got %q", tc.cpus, tc.mask, got)
+		}
+		m, err := ParseCPUMask(tc.mask)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !m.Equal(s) {
+			t.Fatalf("%q: expected %v, got %v", tc.mask, s, m)
+		}
+		l, err := ParseCPUList(tc.list)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !l.Equal(s) {
+			t.Fatalf("%q: expected %v, got %v", tc.list, s, l)
+		}
+	}
+}
+
+func TestCPUSetOps(t *testing.T) {
+	a := NewCPUSet(0, 1, 2, 3, 70)
+	b := NewCPUSet(2, 3, 4, 5)
+
+	for _, tc := range []struct {
+		name string
+		got  CPUSet
+		want string
+	}{
+		{"union", a.Union(b), "0-5,70"},
+		{"intersect", a.Intersect(b), "2-3"},
+		{"difference", a.Difference(b), "0-1,70"},
+		{"reverse difference", b.Difference(a), "4-5"},
+	} {
+		if got := tc.got.String(); got != tc.want {
+			t.Fatalf("%s: expected %q, got %q", tc.name, tc.want, got)
+		}
+	}
+	if a.Len() != 5 || a.Max() != 70 {
+		t.Fatalf("expected len 5 and max 70, got %d and %d", a.Len(), a.Max())
+	}
+	if !NewCPUSet(2, 3).IsSubset(a) || b.IsSubset(a) {
+		t.Fatal("IsSubset is incorrect")
+	}
+
+	c := a.Clone()
+	c.Clear(70)
+	if !a.Has(70) || c.Has(70) {
+		t.Fatal("Clone shares storage")
+	}
+	if !c.Equal(NewCPUSet(0, 1, 2, 3)) {
+		t.Fatalf("expected %v, got %v", NewCPUSet(0, 1, 2, 3), c)
+	}
+}
+
+func TestCPUSetJSON(t *testing.T) {
+	want := NewCPUSet(0, 1, 2, 3, 8)
+	buf, err := json.Marshal(want)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(buf) != `"0-3,8"` {
+		t.Fatalf("unexpected JSON: %s", buf)
+	}
+	var got CPUSet
+	if err := json.Unmarshal(buf, &got); err != nil {
+		t.Fatal(err)
+	}
+	if !got.Equal(want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+}
@@ -0,0 +1,277 @@
+package sysinfo
+
+import (
+	"bytes"
+	"errors"
+	"sort"
+	"time"
+)
+
+// CPUTimes is the cumulative time a CPU has spent in each
+// mode, in clock ticks (USER_HZ, usually 1/100th of
+// a second).
+//
+// On Linux, this information is read from the "cpu" lines of
+// /proc/stat.
+type CPUTimes struct {
+	// Proc is the processor number, matching CPU.Proc, or -1
+	// for the aggregate of every CPU.
+	Proc int `json:"processor"`
+	// User is time spent in user mode, including Guest.
+	User uint64 `json:"user"`
+	// Nice is time spent in user mode with low priority,
+	// including GuestNice.
+	Nice uint64 `json:"nice"`
+	// System is time spent in kernel mode.
+	System uint64 `json:"system"`
+	// Idle is time spent idle.
+	Idle uint64 `json:"idle"`
+	// IOWait is time spent idle while waiting for I/O.
+	IOWait uint64 `json:"iowait"`
+	// IRQ is time spent servicing hardware interrupts.
+	IRQ uint64 `json:"irq"`
+	// SoftIRQ is time spent servicing software interrupts.
+	SoftIRQ uint64 `json:"softirq"`
+	// Steal is time stolen by the hypervisor to run other
+	// virtual machines.
+	Steal uint64 `json:"steal"`
+	// Guest is time spent running a virtual CPU for a guest.
+	Guest uint64 `json:"guest"`
+	// GuestNice is time spent running a low priority virtual
+	// CPU for a guest.
+	GuestNice uint64 `json:"guest_nice"`
+}
+
+// Total returns the total time.
+//
+// Guest and GuestNice are not included since they are
+// already counted in User and Nice.
+func (t CPUTimes) Total() uint64 {
+	return t.User + t.Nice + t.System + t.Idle + t.IOWait +
+		t.IRQ + t.SoftIRQ + t.Steal
+}
+
+// CPUSample is a snapshot of CPU times.
+type CPUSample struct {
+	// Time is when the sample was taken.
+	Time time.Time `json:"time"`
+	// Total is the aggregate of every CPU.
+	Total CPUTimes `json:"total"`
+	// CPUs is per-CPU times.
+	//
+	// CPUs is sorted by the Proc field in ascending order
+	// and only includes online CPUs.
+	CPUs []CPUTimes `json:"cpus"`
+}
+
+// SampleCPU reads the current CPU times.
+//
+// Use Utilization to compare two samples.
+func SampleCPU() (CPUSample, error) {
+	return sampleHostCPU()
+}
+
+var errUnsupported = errors.New("sysinfo: not supported on this platform")
+
+var (
+	errProcStat      = errors.New("sysinfo: invalid /proc/stat")
+	errProcStatNoCPU = errors.New("sysinfo: /proc/stat has no cpu line")
+)
+
+// parseProcStat parses the "cpu" lines of /proc/stat.
+//
+// They should look like
+//
+//	cpu  4705 356 584 3699 23 23 0 0 0 0
+//	cpu0 1393280 32966 572056 13343292 6130 0 17875 0 23933 0
+//
+// Lines with fewer columns, written by older kernels, leave
+// the remaining fields zero.
+func parseProcStat(buf []byte) (CPUSample, error) {
+	var s CPUSample
+	if err := s.parse(buf); err != nil {
+		return CPUSample{}, err
+	}
+	return s, nil
+}
+
+// parse parses /proc/stat into s, reusing s.CPUs.
+//
+// It does not allocate unless s.CPUs needs to grow.
+func (s *CPUSample) parse(buf []byte) error {
+	s.Total = CPUTimes{}
+	s.CPUs = s.CPUs[:0]
+	found := false
+	sorted := true
+	for len(buf) > 0 {
+		line := buf
+		if i := bytes.IndexByte(buf, '\n'); i >= 0 {
+			line, buf = buf[:i], buf[i+1:]
+		} else {
+			buf = nil
+		}
+		name, rest := nextField(line)
+		if len(name) < len("cpu") || string(name[:3]) != "cpu" {
+			continue
+		}
+		t := CPUTimes{Proc: -1}
+		if len(name) > len("cpu") {
+			n, ok := atoiBytes(name[len("cpu"):])
+			if !ok {
+				continue
+			}
+			t.Proc = n
+		}
+		fields := [...]*uint64{
+			&t.User, &t.Nice, &t.System, &t.Idle, &t.IOWait,
+			&t.IRQ, &t.SoftIRQ, &t.Steal, &t.Guest, &t.GuestNice,
+		}
+		n := 0
+		for _, p := range fields {
+			var f []byte
+			f, rest = nextField(rest)
+			if f == nil {
+				break
+			}
+			v, ok := parseUintBytes(f)
+			if !ok {
+				return errProcStat
+			}
+			*p = v
+			n++
+		}
+		if n < 4 {
+			continue
+		}
+		if t.Proc < 0 {
+			s.Total = t
+			found = true
+		} else {
+			if k := len(s.CPUs); k > 0 && s.CPUs[k-1].Proc > t.Proc {
+				sorted = false
+			}
+			s.CPUs = append(s.CPUs, t)
+		}
+	}
+	if !found {
+		return errProcStatNoCPU
+	}
+	if !sorted {
+		sort.Slice(s.CPUs, func(i, j int) bool {
+			return s.CPUs[i].Proc < s.CPUs[j].Proc
+		})
+	}
+	return nil
+}
+
+// nextField returns the first space-separated field in b and
+// the remainder, or nil if there are no more fields.
+func nextField(b []byte) (field, rest []byte) {
+	i := 0
+	for i < len(b) && (b[i] == ' ' || b[i] == '\t') {
+		i++
+	}
+	if i == len(b) {
+		return nil, nil
+	}
+	j := i
+	for j < len(b) && b[j] != ' ' && b[j] != '\t' {
+		j++
+	}
+	return b[i:j], b[j:]
+}
+
+// Utilization is CPU utilization between two samples.
+type Utilization struct {
+	// Total is the aggregate utilization of every CPU.
+	Total CPUUtil `json:"total"`
+	// CPUs is per-CPU utilization.
+	//
+	// CPUs is sorted by the Proc field in ascending order and
+	// only includes CPUs that are in both samples.
+	CPUs []CPUUtil `json:"cpus"`
+}
+
+// CPUUtil is the fraction of time, in [0, 1], that a CPU
+// spent in each mode between two samples.
+type CPUUtil struct {
+	// Proc is the processor number, matching CPU.Proc, or -1
+	// for the aggregate of every CPU.
+	Proc      int     `json:"processor"`
+	User      float64 `json:"user"`
+	Nice      float64 `json:"nice"`
+	System    float64 `json:"system"`
+	Idle      float64 `json:"idle"`
+	IOWait    float64 `json:"iowait"`
+	IRQ       float64 `json:"irq"`
+	SoftIRQ   float64 `json:"softirq"`
+	Steal     float64 `json:"steal"`
+	Guest     float64 `json:"guest"`
+	GuestNice float64 `json:"guest_nice"`
+}
+
+// Busy returns the fraction of time the CPU was not idle or
+// waiting for I/O.
+func (u CPUUtil) Busy() float64 {
+	return 1 - u.Idle - u.IOWait
+}
+
+// Utilization returns the CPU utilization between two
+// samples, where prev was taken before cur.
+func (cur CPUSample) Utilization(prev CPUSample) Utilization {
+	var u Utilization
+	cur.utilizationInto(&u, prev)
+	return u
+}
+
+// utilizationInto is like Utilization, but stores the result
+// in u and reuses u.CPUs.
+func (cur CPUSample) utilizationInto(u *Utilization, prev CPUSample) {
+	u.Total = cpuUtil(prev.Total, cur.Total)
+	u.CPUs = u.CPUs[:0]
+	i, j := 0, 0
+	for i < len(prev.CPUs) && j < len(cur.CPUs) {
+		a, b := prev.CPUs[i], cur.CPUs[j]
+		switch {
+		case a.Proc < b.Proc:
+			i++
+		case a.Proc > b.Proc:
+			j++
+		default:
+			u.CPUs = append(u.CPUs, cpuUtil(a, b))
+			i++
+			j++
+		}
+	}
+}
+
+func cpuUtil(a, b CPUTimes) CPUUtil {
+	u := CPUUtil{Proc: b.Proc}
+	total := float64(delta(a.Total(), b.Total()))
+	if total == 0 {
+		return u
+	}
+	frac := func(x, y uint64) float64 {
+		return float64(delta(x, y)) / total
+	}
+	u.User = frac(a.User, b.User)
+	u.Nice = frac(a.Nice, b.Nice)
+	u.System = frac(a.System, b.System)
+	u.Idle = frac(a.Idle, b.Idle)
+	u.IOWait = frac(a.IOWait, b.IOWait)
+	u.IRQ = frac(a.IRQ, b.IRQ)
+	u.SoftIRQ = frac(a.SoftIRQ, b.SoftIRQ)
+	u.Steal = frac(a.Steal, b.Steal)
+	u.Guest = frac(a.Guest, b.Guest)
+	u.GuestNice = frac(a.GuestNice, b.GuestNice)
+	return u
+}
+
+// delta returns y-x, or zero if the counter went backward,
+// which happens when a CPU is hotplugged.
+func delta(x, y uint64) uint64 {
+	if y < x {
+		return 0
+	}
+	return y - x
+}
@@ -0,0 +1,99 @@
+package sysinfo
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestParseProcStat(t *testing.T) {
+	const stat = `cpu  2000 100 1000 6000 200 50 50 600 300 0
+cpu0 1000 100 500 3000 100 25 25 300 300 0
+cpu1 1000 0 500 3000 100 25 25 300 0 0
+intr 114930548 113199788 3 0 5 263 0 4 [... lots more numbers ...]
+ctxt 1990473
+btime 1062191376
+processes 2915
+procs_running 1
+procs_blocked 0
+`
+	got, err := parseProcStat([]byte(stat))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := CPUSample{
+		Total: CPUTimes{Proc: -1, User: 2000, Nice: 100, System: 1000, Idle: 6000, IOWait: 200, IRQ: 50, SoftIRQ: 50, Steal: 600, Guest: 300},
+		CPUs: []CPUTimes{
+			{Proc: 0, User: 1000, Nice: 100, System: 500, Idle: 3000, IOWait: 100, IRQ: 25, SoftIRQ: 25, Steal: 300, Guest: 300},
+			{Proc: 1, User: 1000, System: 500, Idle: 3000, IOWait: 100, IRQ: 25, SoftIRQ: 25, Steal: 300},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %#v, got %#v", want, got)
+	}
+	if n := got.Total.Total(); n != 10000 {
+		t.Fatalf("expected 10000, got %d", n)
+	}
+
+	// Older kernels have fewer columns.
+	got, err = parseProcStat([]byte("cpu 1 2 3 4\ncpu0 1 2 3 4\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Total != (CPUTimes{Proc: -1, User: 1, Nice: 2, System: 3, Idle: 4}) {
+		t.Fatalf("unexpected total: %#v", got.Total)
+	}
+
+	if _, err := parseProcStat([]byte("intr 1 2 3\n")); err == nil {
+		t.Fatal("expected an error")
+	}
+}
+
+func TestUtilization(t *testing.T) {
+	prev := CPUSample{
+		Total: CPUTimes{Proc: -1, User: 100, Idle: 100},
+		CPUs: []CPUTimes{
+			{Proc: 0, User: 50, Idle: 50},
+			{Proc: 1, User: 50, Idle: 50},
+			{Proc: 3, User: 50, Idle: 50},
+		},
+	}
+	cur := CPUSample{
+		Total: CPUTimes{Proc: -1, User: 200, System: 20, Idle: 240, IOWait: 10, Steal: 30},
+		CPUs: []CPUTimes{
+			{Proc: 0, User: 150, System: 20, Idle: 50, Steal: 30},
+			// CPU 1 was hotplugged, resetting its counters.
+			{Proc: 1, User: 1, Idle: 1},
+			{Proc: 2, User: 50, Idle: 50},
+			{Proc: 3, User: 50, Idle: 190, IOWait: 10},
+		},
+	}
+	u := cur.Utilization(prev)
+
+	approx := func(name string, got, want float64) {
+		t.Helper()
+		if math.Abs(got-want) > 1e-9 {
+			t.Fatalf("%s: expected %v, got %v", name, want, got)
+		}
+	}
+	approx("total user", u.Total.User, 100.0/300)
+	approx("total steal", u.Total.Steal, 30.0/300)
+	approx("total busy", u.Total.Busy(), 150.0/300)
+
+	if len(u.CPUs) != 3 {
+		t.Fatalf("expected 3 CPUs, got %d", len(u.CPUs))
+	}
+	for i, proc := range []int{0, 1, 3} {
+		if u.CPUs[i].Proc != proc {
+			t.Fatalf("#%d: expected CPU %d, got %d", i, proc, u.CPUs[i].Proc)
+		}
+	}
+	approx("cpu0 user", u.CPUs[0].User, 100.0/150)
+	approx("cpu0 steal", u.CPUs[0].Steal, 30.0/150)
+	approx("cpu0 busy", u.CPUs[0].Busy(), 1)
+	if u.CPUs[1] != (CPUUtil{Proc: 1}) {
+		t.Fatalf("unexpected utilization after hotplug: %#v", u.CPUs[1])
+	}
+	approx("cpu3 idle", u.CPUs[2].Idle, 140.0/150)
+	approx("cpu3 iowait", u.CPUs[2].IOWait, 10.0/150)
+}
@@ -0,0 +1,40 @@
+package sysinfo
+
+import (
+	"io/fs"
+)
+
+// detectFS detects the host information of a Linux host from
+// its /proc and /sys file systems.
+//
+// fsys should be rooted at "/". allowed is the set of CPUs
+// the current process is allowed to run on.
+func detectFS(fsys fs.FS, allowed CPUSet) Info {
+	buf, err := fs.ReadFile(fsys, "proc/cpuinfo")
+	if err != nil {
+		return Info{}
+	}
+	var v Info
+	scanProc(&v, buf)
+	v.Masks = readCPUMasks(fsys)
+	v.Masks.Allowed = allowed
+	applyCPUMasks(&v)
+	v.NUMA = readNUMA(fsys)
+	v.System = readDMI(fsys)
+	v.Kernel = readString(fsys, "proc/sys/kernel/osrelease")
+	if s, err := readSMBIOS(fsys); err == nil {
+		v.SMBIOS = s
+	}
+	readDeviceTree(fsys, &v.Board)
+	decodeBoard(&v.Board)
+	applyDeviceTreeCPUs(fsys, &v)
+	v.Security = readSecurity(fsys)
+	v.Thermal = readThermal(fsys)
+	v.Power = readPower(fsys)
+	readCPUIdle(fsys, &v)
+	readGovernors(fsys, &v)
+	v.Runtime = readRuntime(fsys)
+	v.Pressure = readPressure(fsys)
+	v.CgroupPressure = readCgroupPressure(fsys)
+	return v
+}
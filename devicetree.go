@@ -0,0 +1,227 @@
+package sysinfo
+
+import (
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// readDeviceTree fills in the parts of b that /proc/cpuinfo
+// did not provide from the device tree.
+//
+// fsys should be rooted at "/".
+func readDeviceTree(fsys fs.FS, b *Board) {
+	const dir = "proc/device-tree/"
+	if b.Model == "" {
+		b.Model = readDTString(fsys, dir+"model")
+	}
+	if b.Serial == "" {
+		b.Serial = readDTString(fsys, dir+"serial-number")
+	}
+	b.Compatible = readDTStrings(fsys, dir+"compatible")
+	vendor, soc := socFromCompatible(b.Compatible)
+	if b.SoCVendor == "" {
+		b.SoCVendor = vendor
+	}
+	if b.SoC == "" {
+		b.SoC = soc
+	}
+}
+
+// applyDeviceTreeCPUs uses each CPU's device tree compatible
+// list to fill in Impl and Part for CPUs where /proc/cpuinfo
+// did not provide a MIDR.
+//
+// fsys should be rooted at "/".
+func applyDeviceTreeCPUs(fsys fs.FS, o *Info) {
+	compat := make(map[int][]string)
+	for _, c := range o.CPUs {
+		name := fmt.Sprintf("sys/devices/system/cpu/cpu%d/of_node/compatible", c.Proc)
+		if list := readDTStrings(fsys, name); len(list) > 0 {
+			compat[c.Proc] = list
+		}
+	}
+	if len(compat) == 0 {
+		// Older kernels do not link CPUs to their device tree
+		// nodes. Assume that logical CPUs are numbered in
+		// unit address order, which is how the kernel assigns
+		// them.
+		for i, list := range readDTCPUNodes(fsys) {
+			compat[i] = list
+		}
+	}
+	for i := range o.CPUs {
+		c := &o.CPUs[i]
+		if c.Impl != 0 || c.Part != 0 {
+			continue
+		}
+		for _, s := range compat[c.Proc] {
+			if impl, part, ok := partFromCompatible(s); ok {
+				c.Impl, c.Part = impl, part
+				break
+			}
+		}
+	}
+}
+
+// readDTCPUNodes returns the compatible list of each CPU node
+// in /sys/firmware/devicetree/base/cpus, ordered by unit
+// address.
+func readDTCPUNodes(fsys fs.FS) [][]string {
+	const dir = "sys/firmware/devicetree/base/cpus"
+	ents, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil
+	}
+	type node struct {
+		addr   uint64
+		compat []string
+	}
+	var nodes []node
+	for _, e := range ents {
+		name := e.Name()
+		if !strings.HasPrefix(name, "cpu@") {
+			continue
+		}
+		addr, err := strconv.ParseUint(strings.TrimPrefix(name, "cpu@"), 16, 64)
+		if err != nil {
+			continue
+		}
+		nodes = append(nodes, node{
+			addr:   addr,
+			compat: readDTStrings(fsys, path.Join(dir, name, "compatible")),
+		})
+	}
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodes[i].addr < nodes[j].addr
+	})
+	list := make([][]string, len(nodes))
+	for i, n := range nodes {
+		list[i] = n.compat
+	}
+	return list
+}
+
+// readDTString reads a NUL-terminated device tree string
+// property.
+func readDTString(fsys fs.FS, name string) string {
+	buf, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimRight(string(buf), "\x00\n")
+}
+
+// readDTStrings reads a device tree string list property,
+// which is a sequence of NUL-terminated strings.
+func readDTStrings(fsys fs.FS, name string) []string {
+	s := readDTString(fsys, name)
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, "\x00")
+}
+
+// socVendors maps device tree vendor prefixes to SoC vendors.
+//
+// See Documentation/devicetree/bindings/vendor-prefixes.yaml
+// in the Linux kernel.
+var socVendors = map[string]string{
+	"allwinner": "Allwinner",
+	"amlogic":   "Amlogic",
+	"apple":     "Apple",
+	"brcm":      "Broadcom",
+	"fsl":       "NXP",
+	"google":    "Google",
+	"hisilicon": "HiSilicon",
+	"marvell":   "Marvell",
+	"mediatek":  "MediaTek",
+	"nvidia":    "NVIDIA",
+	"nxp":       "NXP",
+	"qcom":      "Qualcomm",
+	"renesas":   "Renesas",
+	"rockchip":  "Rockchip",
+	"samsung":   "Samsung",
+	"sifive":    "SiFive",
+	"socionext": "Socionext",
+	"sophgo":    "Sophgo",
+	"st":        "STMicroelectronics",
+	"starfive":  "StarFive",
+	"thead":     "T-HEAD",
+	"ti":        "Texas Instruments",
+	"xlnx":      "Xilinx",
+}
+
+// socFromCompatible finds the SoC in a board's compatible
+// list.
+//
+// The list runs from most to least specific, for example
+//
+//	pine64,rockpro64-v2.1
+//	pine64,rockpro64
+//	rockchip,rk3399
+//
+// so the SoC is the last entry from a known SoC vendor.
+func socFromCompatible(list []string) (vendor, soc string) {
+	for i := len(list) - 1; i >= 0; i-- {
+		v, name := splitCompatible(list[i])
+		vendor, ok := socVendors[v]
+		if !ok {
+			continue
+		}
+		if v == "allwinner" {
+			// allwinner,sun50i-h6
+			if j := strings.IndexByte(name, '-'); j >= 0 {
+				name = name[j+1:]
+			}
+		}
+		return vendor, strings.ToUpper(name)
+	}
+	return "", ""
+}
+
+// partFromCompatible maps a CPU node's compatible string, for
+// example "arm,cortex-a53", to its Implementer and Part.
+func partFromCompatible(s string) (Implementer, Part, bool) {
+	vendor, name := splitCompatible(s)
+	switch vendor {
+	case "arm":
+		for _, p := range armParts {
+			if strings.ToLower(armPartName(p)) == name {
+				return ARMLtd, p, true
+			}
+		}
+	case "apple":
+		switch name {
+		case "firestorm":
+			return Apple, Firestorm, true
+		case "icestorm":
+			return Apple, Icestorm, true
+		}
+	case "nvidia":
+		if name == "carmel" {
+			return NVIDIA, Carmel, true
+		}
+	case "qcom":
+		switch name {
+		case "krait":
+			return Qualcomm, Krait, true
+		case "kryo":
+			return Qualcomm, Kryo, true
+		case "falkor":
+			return Qualcomm, Falkor, true
+		}
+	}
+	return 0, 0, false
+}
+
+func splitCompatible(s string) (vendor, name string) {
+	i := strings.IndexByte(s, ',')
+	if i < 0 {
+		return "", s
+	}
+	return s[:i], s[i+1:]
+}
@@ -0,0 +1,143 @@
+package sysinfo
+
+import (
+	"reflect"
+	"testing"
+	"testing/fstest"
+)
+
+func TestReadDeviceTree(t *testing.T) {
+	for _, tc := range []struct {
+		name  string
+		fsys  fstest.MapFS
+		board Board
+		want  Board
+	}{
+		{
+			name: "rockpro64",
+			fsys: fstest.MapFS{
+				"proc/device-tree/model":         {Data: []byte("Pine64 RockPro64 v2.1\x00")},
+				"proc/device-tree/compatible":    {Data: []byte("pine64,rockpro64-v2.1\x00pine64,rockpro64\x00rockchip,rk3399\x00")},
+				"proc/device-tree/serial-number": {Data: []byte("a1b2c3d4e5f60708\x00")},
+			},
+			want: Board{
+				Model:      "Pine64 RockPro64 v2.1",
+				SoC:        "RK3399",
+				SoCVendor:  "Rockchip",
+				Compatible: []string{"pine64,rockpro64-v2.1", "pine64,rockpro64", "rockchip,rk3399"},
+				Serial:     "a1b2c3d4e5f60708",
+			},
+		},
+		{
+			name: "raspberry_pi_4b",
+			fsys: fstest.MapFS{
+				"proc/device-tree/model":      {Data: []byte("Raspberry Pi 4 Model B Rev 1.4\x00")},
+				"proc/device-tree/compatible": {Data: []byte("raspberrypi,4-model-b\x00brcm,bcm2711\x00")},
+			},
+			board: Board{RevisionCode: "b03114", Serial: "10000000771c4af4"},
+			want: Board{
+				Model:        "Raspberry Pi 4 Model B Rev 1.4",
+				Name:         "Raspberry Pi 4 Model B",
+				Manufacturer: "Sony UK",
+				SoC:          "BCM2711",
+				SoCVendor:    "Broadcom",
+				Compatible:   []string{"raspberrypi,4-model-b", "brcm,bcm2711"},
+				Memory:       2 << 30,
+				Revision:     "1.4",
+				RevisionCode: "b03114",
+				Serial:       "10000000771c4af4",
+			},
+		},
+		{
+			name: "pine_h64",
+			fsys: fstest.MapFS{
+				"proc/device-tree/compatible": {Data: []byte("pine64,pine-h64\x00allwinner,sun50i-h6\x00")},
+			},
+			want: Board{
+				SoC:        "H6",
+				SoCVendor:  "Allwinner",
+				Compatible: []string{"pine64,pine-h64", "allwinner,sun50i-h6"},
+			},
+		},
+		{
+			name: "cpuinfo model",
+			fsys: fstest.MapFS{
+				"proc/device-tree/model": {Data: []byte("ignored\x00")},
+			},
+			board: Board{Model: "from cpuinfo"},
+			want:  Board{Model: "from cpuinfo"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			b := tc.board
+			readDeviceTree(tc.fsys, &b)
+			decodeBoard(&b)
+			if !reflect.DeepEqual(b, tc.want) {
+				t.Fatalf("expected %#v, got %#v", tc.want, b)
+			}
+		})
+	}
+}
+
+func TestApplyDeviceTreeCPUs(t *testing.T) {
+	a53 := []byte("arm,cortex-a53\x00arm,armv8\x00")
+	a72 := []byte("arm,cortex-a72\x00arm,armv8\x00")
+
+	// Without of_node links, CPUs are matched by unit
+	// address.
+	fsys := fstest.MapFS{
+		"sys/firmware/devicetree/base/cpus/cpu@0/compatible":   {Data: a53},
+		"sys/firmware/devicetree/base/cpus/cpu@1/compatible":   {Data: a53},
+		"sys/firmware/devicetree/base/cpus/cpu@100/compatible": {Data: a72},
+		"sys/firmware/devicetree/base/cpus/cpu@101/compatible": {Data: a72},
+		"sys/firmware/devicetree/base/cpus/cpu-map/cluster0":   {Data: nil},
+	}
+	v := Info{CPUs: []CPU{
+		{Proc: 0}, {Proc: 1}, {Proc: 2},
+		{Proc: 3, Impl: Qualcomm, Part: Kryo},
+	}}
+	applyDeviceTreeCPUs(fsys, &v)
+	want := []CPU{
+		{Proc: 0, Impl: ARMLtd, Part: CortexA53},
+		{Proc: 1, Impl: ARMLtd, Part: CortexA53},
+		{Proc: 2, Impl: ARMLtd, Part: CortexA72},
+		{Proc: 3, Impl: Qualcomm, Part: Kryo},
+	}
+	if !reflect.DeepEqual(v.CPUs, want) {
+		t.Fatalf("expected %#v, got %#v", want, v.CPUs)
+	}
+
+	// of_node links take precedence.
+	fsys["sys/devices/system/cpu/cpu0/of_node/compatible"] = &fstest.MapFile{Data: a72}
+	v = Info{CPUs: []CPU{{Proc: 0}, {Proc: 1}}}
+	applyDeviceTreeCPUs(fsys, &v)
+	want = []CPU{
+		{Proc: 0, Impl: ARMLtd, Part: CortexA72},
+		{Proc: 1},
+	}
+	if !reflect.DeepEqual(v.CPUs, want) {
+		t.Fatalf("expected %#v, got %#v", want, v.CPUs)
+	}
+}
+
+func TestPartFromCompatible(t *testing.T) {
+	for _, tc := range []struct {
+		s    string
+		impl Implementer
+		part Part
+		ok   bool
+	}{
+		{"arm,cortex-a76", ARMLtd, CortexA76, true},
+		{"arm,neoverse-n1", ARMLtd, NeoverseN1, true},
+		{"apple,icestorm", Apple, Icestorm, true},
+		{"qcom,kryo", Qualcomm, Kryo, true},
+		{"arm,armv8", 0, 0, false},
+		{"riscv", 0, 0, false},
+	} {
+		impl, part, ok := partFromCompatible(tc.s)
+		if impl != tc.impl || part != tc.part || ok != tc.ok {
+			t.Fatalf("%q: expected (%v, %#x, %t), got (%v, %#x, %t)",
+				tc.s, tc.impl, tc.part, tc.ok, impl, part, ok)
+		}
+	}
+}
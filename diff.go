@@ -0,0 +1,463 @@
+package sysinfo
+
+import (
+	"encoding"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ChangeKind describes how a value changed between two Info
+// snapshots.
+type ChangeKind uint8
+
+const (
+	ChangeModified ChangeKind = iota // modified
+	ChangeAdded                      // added
+	ChangeRemoved                    // removed
+)
+
+func (k ChangeKind) MarshalText() ([]byte, error) {
+	return []byte(k.String()), nil
+}
+
+func (k *ChangeKind) UnmarshalText(text []byte) error {
+	v, err := parseEnum("ChangeKind", string(text), func(i uint8) string {
+		return ChangeKind(i).String()
+	})
+	if err != nil {
+		return err
+	}
+	*k = ChangeKind(v)
+	return nil
+}
+
+// Change is a single difference between two Info snapshots.
+type Change struct {
+	// Kind is how the value changed.
+	Kind ChangeKind `json:"kind"`
+	// Path identifies the value using the JSON field names,
+	// for example "cpus[3].microcode_version",
+	// "misc[Kernel Version]", or
+	// "security.vulnerabilities[spectre_v2].status".
+	//
+	// CPUs are identified by their Proc field, map entries,
+	// such as "cpus[0].extra[vmx flags]", by their key, and
+	// other elements by their Name field, if any, or index.
+	Path string `json:"path"`
+	// From is the old value, or empty if the value was added.
+	From string `json:"from,omitempty"`
+	// To is the new value, or empty if the value was removed.
+	To string `json:"to,omitempty"`
+}
+
+// String returns the change in the form
+//
+//	~ cpus[0].microcode_version: 240 -> 248
+//	+ cpus[0].features: avx512f
+//
+// Added and removed values without a From or To, such as
+// CPUs, only include the path.
+func (c Change) String() string {
+	switch c.Kind {
+	case ChangeAdded:
+		if c.To == "" {
+			return "+ " + c.Path
+		}
+		return fmt.Sprintf("+ %s: %s", c.Path, c.To)
+	case ChangeRemoved:
+		if c.From == "" {
+			return "- " + c.Path
+		}
+		return fmt.Sprintf("- %s: %s", c.Path, c.From)
+	default:
+		return fmt.Sprintf("~ %s: %s -> %s", c.Path, c.From, c.To)
+	}
+}
+
+// DefaultFreqTolerance is the default DiffOptions.FreqTolerance.
+const DefaultFreqTolerance = 0.05
+
+// DiffOptions configures Diff.
+type DiffOptions struct {
+	// FreqTolerance is the relative change in a CPU's
+	// frequency, for example 0.05 for 5%, below which the
+	// change is treated as noise and not reported.
+	//
+	// If zero, DefaultFreqTolerance is used. If negative,
+	// every change is reported.
+	FreqTolerance float64
+}
+
+// Diff returns the differences between a and b using the
+// default options.
+//
+// See DiffOptions.Diff.
+func Diff(a, b Info) []Change {
+	return DiffOptions{}.Diff(a, b)
+}
+
+// Diff returns the differences between a and b, where a is
+// the older snapshot.
+//
+// CPUs are matched by their Proc field and Misc by key.
+// Features, bugs, and other sets of strings are compared as
+// sets, with a Change for each added or removed element.
+//
+// Readings that change from moment to moment, such as
+// temperatures, energy counters, idle state counters, free
+// memory, load, and pressure, are not compared.
+func (o DiffOptions) Diff(a, b Info) []Change {
+	if o.FreqTolerance == 0 {
+		o.FreqTolerance = DefaultFreqTolerance
+	}
+	d := differ{opts: o}
+	d.cpus(a.CPUs, b.CPUs)
+	d.misc(a.Misc, b.Misc)
+	av, bv := reflect.ValueOf(a), reflect.ValueOf(b)
+	t := av.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.Name == "CPUs" || f.Name == "Misc" || diffSkipInfo[f.Name] {
+			continue
+		}
+		d.value(fieldName(f), av.Field(i), bv.Field(i))
+	}
+	return d.changes
+}
+
+// diffSkipInfo is the set of Info fields that are not
+// compared because they change from moment to moment.
+var diffSkipInfo = map[string]bool{
+	"Thermal":        true,
+	"Power":          true,
+	"Runtime":        true,
+	"Pressure":       true,
+	"CgroupPressure": true,
+}
+
+// diffSkip is the set of struct fields that are not compared
+// because they change from moment to moment.
+var diffSkip = map[reflect.Type]map[string]bool{
+	reflect.TypeOf(IdleState{}): {"Usage": true, "Time": true},
+	reflect.TypeOf(NUMANode{}):  {"MemFree": true},
+}
+
+type differ struct {
+	opts    DiffOptions
+	changes []Change
+}
+
+func (d *differ) add(kind ChangeKind, path, from, to string) {
+	d.changes = append(d.changes, Change{
+		Kind: kind,
+		Path: path,
+		From: from,
+		To:   to,
+	})
+}
+
+func (d *differ) cpus(a, b []CPU) {
+	am := make(map[int]CPU, len(a))
+	for _, c := range a {
+		am[c.Proc] = c
+	}
+	bm := make(map[int]CPU, len(b))
+	for _, c := range b {
+		bm[c.Proc] = c
+	}
+	var procs []int
+	for proc := range am {
+		procs = append(procs, proc)
+	}
+	for proc := range bm {
+		if _, ok := am[proc]; !ok {
+			procs = append(procs, proc)
+		}
+	}
+	sort.Ints(procs)
+	for _, proc := range procs {
+		path := fmt.Sprintf("cpus[%d]", proc)
+		x, inA := am[proc]
+		y, inB := bm[proc]
+		switch {
+		case !inA:
+			d.add(ChangeAdded, path, "", "")
+		case !inB:
+			d.add(ChangeRemoved, path, "", "")
+		default:
+			d.cpu(path, x, y)
+		}
+	}
+}
+
+func (d *differ) cpu(path string, a, b CPU) {
+	av, bv := reflect.ValueOf(a), reflect.ValueOf(b)
+	t := av.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.Name == "Freq" {
+			if freqChanged(a.Freq, b.Freq, d.opts.FreqTolerance) {
+				d.add(ChangeModified, path+"."+fieldName(f),
+					formatFloat(a.Freq), formatFloat(b.Freq))
+			}
+			continue
+		}
+		d.value(path+"."+fieldName(f), av.Field(i), bv.Field(i))
+	}
+}
+
+// freqChanged reports whether the relative change from a to
+// b is greater than tol.
+func freqChanged(a, b, tol float64) bool {
+	if tol < 0 || a == 0 || b == 0 {
+		return a != b
+	}
+	return math.Abs(b-a)/a > tol
+}
+
+func (d *differ) misc(a, b []Pair) {
+	var ak, bk []string
+	am := make(map[string]string, len(a))
+	for _, p := range a {
+		am[p.Key] = p.Value
+		ak = append(ak, p.Key)
+	}
+	bm := make(map[string]string, len(b))
+	for _, p := range b {
+		bm[p.Key] = p.Value
+		bk = append(bk, p.Key)
+	}
+	for _, k := range sortedUnion(ak, bk) {
+		path := "misc[" + k + "]"
+		x, inA := am[k]
+		y, inB := bm[k]
+		switch {
+		case !inA:
+			d.add(ChangeAdded, path, "", y)
+		case !inB:
+			d.add(ChangeRemoved, path, x, "")
+		case x != y:
+			d.add(ChangeModified, path, x, y)
+		}
+	}
+}
+
+var textMarshaler = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+
+// value compares two values of the same type.
+func (d *differ) value(path string, a, b reflect.Value) {
+	if a.Type().Implements(textMarshaler) {
+		x, y := formatValue(a), formatValue(b)
+		if x != y {
+			d.add(ChangeModified, path, x, y)
+		}
+		return
+	}
+	switch a.Kind() {
+	case reflect.Ptr:
+		switch {
+		case a.IsNil() && b.IsNil():
+		case a.IsNil():
+			d.add(ChangeAdded, path, "", "")
+		case b.IsNil():
+			d.add(ChangeRemoved, path, "", "")
+		default:
+			d.value(path, a.Elem(), b.Elem())
+		}
+	case reflect.Struct:
+		t := a.Type()
+		skip := diffSkip[t]
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			name := fieldName(f)
+			if f.PkgPath != "" || name == "-" || skip[f.Name] {
+				continue
+			}
+			d.value(path+"."+name, a.Field(i), b.Field(i))
+		}
+	case reflect.Slice, reflect.Array:
+		if a.Type().Elem().Kind() == reflect.String {
+			d.strings(path, a, b)
+		} else {
+			d.slice(path, a, b)
+		}
+	case reflect.Map:
+		d.mapValue(path, a, b)
+	default:
+		if a.Interface() != b.Interface() {
+			d.add(ChangeModified, path, formatValue(a), formatValue(b))
+		}
+	}
+}
+
+// mapValue compares two maps with string keys.
+func (d *differ) mapValue(path string, a, b reflect.Value) {
+	keys := func(v reflect.Value) []string {
+		var list []string
+		for _, k := range v.MapKeys() {
+			list = append(list, k.String())
+		}
+		return list
+	}
+	for _, k := range sortedUnion(keys(a), keys(b)) {
+		p := path + "[" + k + "]"
+		kv := reflect.ValueOf(k)
+		x, y := a.MapIndex(kv), b.MapIndex(kv)
+		switch {
+		case !x.IsValid():
+			d.add(ChangeAdded, p, "", formatValue(y))
+		case !y.IsValid():
+			d.add(ChangeRemoved, p, formatValue(x), "")
+		default:
+			d.value(p, x, y)
+		}
+	}
+}
+
+// strings compares two lists of strings as sets.
+func (d *differ) strings(path string, a, b reflect.Value) {
+	as, al := stringSet(a)
+	bs, bl := stringSet(b)
+	for _, s := range sortedUnion(al, bl) {
+		switch {
+		case !as[s]:
+			d.add(ChangeAdded, path, "", s)
+		case !bs[s]:
+			d.add(ChangeRemoved, path, s, "")
+		}
+	}
+}
+
+func stringSet(v reflect.Value) (map[string]bool, []string) {
+	set := make(map[string]bool, v.Len())
+	var list []string
+	for i := 0; i < v.Len(); i++ {
+		s := v.Index(i).String()
+		if !set[s] {
+			set[s] = true
+			list = append(list, s)
+		}
+	}
+	return set, list
+}
+
+// slice compares two lists, matching elements by their Name
+// field if they have unique names, or by index otherwise.
+func (d *differ) slice(path string, a, b reflect.Value) {
+	ak, aok := sliceKeys(a)
+	bk, bok := sliceKeys(b)
+	if !aok || !bok {
+		ak, bk = indexKeys(a.Len()), indexKeys(b.Len())
+	}
+	am := make(map[string]int, len(ak))
+	for i, k := range ak {
+		am[k] = i
+	}
+	bm := make(map[string]int, len(bk))
+	for i, k := range bk {
+		bm[k] = i
+	}
+	for _, k := range mergeKeys(ak, bk) {
+		p := path + "[" + k + "]"
+		i, inA := am[k]
+		j, inB := bm[k]
+		switch {
+		case !inA:
+			d.add(ChangeAdded, p, "", "")
+		case !inB:
+			d.add(ChangeRemoved, p, "", "")
+		default:
+			d.value(p, a.Index(i), b.Index(j))
+		}
+	}
+}
+
+// sliceKeys returns the Name field of each element, and
+// whether every element has a unique, non-empty name.
+func sliceKeys(v reflect.Value) ([]string, bool) {
+	t := v.Type().Elem()
+	if t.Kind() != reflect.Struct {
+		return nil, false
+	}
+	f, ok := t.FieldByName("Name")
+	if !ok || f.Type.Kind() != reflect.String {
+		return nil, false
+	}
+	keys := make([]string, v.Len())
+	seen := make(map[string]bool, v.Len())
+	for i := range keys {
+		k := v.Index(i).FieldByIndex(f.Index).String()
+		if k == "" || seen[k] {
+			return nil, false
+		}
+		seen[k] = true
+		keys[i] = k
+	}
+	return keys, true
+}
+
+func indexKeys(n int) []string {
+	keys := make([]string, n)
+	for i := range keys {
+		keys[i] = strconv.Itoa(i)
+	}
+	return keys
+}
+
+// mergeKeys returns the unique keys in a followed by the
+// unique keys only in b, preserving their order.
+func mergeKeys(a, b []string) []string {
+	seen := make(map[string]bool, len(a))
+	var keys []string
+	for _, list := range [][]string{a, b} {
+		for _, k := range list {
+			if !seen[k] {
+				seen[k] = true
+				keys = append(keys, k)
+			}
+		}
+	}
+	return keys
+}
+
+// sortedUnion returns the strings in a or b in ascending
+// order.
+func sortedUnion(a, b []string) []string {
+	keys := mergeKeys(a, b)
+	sort.Strings(keys)
+	return keys
+}
+
+// fieldName returns the field's JSON name.
+func fieldName(f reflect.StructField) string {
+	tag := f.Tag.Get("json")
+	if i := strings.IndexByte(tag, ','); i >= 0 {
+		tag = tag[:i]
+	}
+	if tag == "" {
+		return f.Name
+	}
+	return tag
+}
+
+func formatValue(v reflect.Value) string {
+	if m, ok := v.Interface().(encoding.TextMarshaler); ok {
+		b, err := m.MarshalText()
+		if err != nil {
+			return fmt.Sprint(v.Interface())
+		}
+		return string(b)
+	}
+	if v.Kind() == reflect.Float64 || v.Kind() == reflect.Float32 {
+		return formatFloat(v.Float())
+	}
+	return fmt.Sprint(v.Interface())
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
@@ -0,0 +1,100 @@
+package sysinfo
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func readTestInfo(t *testing.T, name string) Info {
+	t.Helper()
+	buf, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var v Info
+	scanProc(&v, buf)
+	return v
+}
+
+func TestDiff(t *testing.T) {
+	a := readTestInfo(t, "raspberry_pi_4b")
+	if changes := Diff(a, readTestInfo(t, "raspberry_pi_4b")); len(changes) != 0 {
+		t.Fatalf("expected no changes, got %v", changes)
+	}
+
+	b := readTestInfo(t, "raspberry_pi_4b")
+	// Gain and lose features.
+	b.CPUs[1].Features = append([]string{"sha2"}, a.CPUs[1].Features[1:]...)
+	// Frequency jitter within the tolerance is ignored.
+	a.CPUs[1].Freq, b.CPUs[1].Freq = 1500, 1520
+	a.CPUs[2].Freq, b.CPUs[2].Freq = 1500, 600
+	b.CPUs[2].Rev = 4
+	b.CPUs[2].Extra = map[string]string{"vmx flags": "ept"}
+	a.CPUs[3].Extra = map[string]string{"quirk": "1", "gone": "x"}
+	b.CPUs[3].Extra = map[string]string{"quirk": "2"}
+	// Drop CPU 0 and add CPU 4.
+	c := b.CPUs[0]
+	c.Proc = 4
+	b.CPUs = append(b.CPUs[1:], c)
+	for i, p := range b.Misc {
+		if p.Key == "Revision" {
+			b.Misc[i].Value = "d03114"
+		}
+	}
+	b.Misc = append(b.Misc, Pair{Key: "Zzz", Value: "new"})
+	a.Security.Vulnerabilities = []Vulnerability{
+		{Name: "spectre_v1", Status: VulnVulnerable},
+		{Name: "spectre_v2", Status: VulnVulnerable},
+	}
+	b.Security.Vulnerabilities = []Vulnerability{
+		{Name: "spectre_v2", Status: VulnMitigated},
+	}
+	b.Masks.Online = NewCPUSet(1, 2, 3, 4)
+	// Readings are not compared.
+	b.Runtime.Load1 = 3
+	b.Thermal.Sensors = []Sensor{{Name: "cpu-thermal", Temp: 50}}
+
+	want := []Change{
+		{Kind: ChangeRemoved, Path: "cpus[0]"},
+		{Kind: ChangeRemoved, Path: "cpus[1].features", From: "half"},
+		{Kind: ChangeAdded, Path: "cpus[1].features", To: "sha2"},
+		{Kind: ChangeModified, Path: "cpus[2].revision", From: "3", To: "4"},
+		{Kind: ChangeModified, Path: "cpus[2].frequency_mhz", From: "1500", To: "600"},
+		{Kind: ChangeAdded, Path: "cpus[2].extra[vmx flags]", To: "ept"},
+		{Kind: ChangeRemoved, Path: "cpus[3].extra[gone]", From: "x"},
+		{Kind: ChangeModified, Path: "cpus[3].extra[quirk]", From: "1", To: "2"},
+		{Kind: ChangeAdded, Path: "cpus[4]"},
+		{Kind: ChangeModified, Path: "misc[Revision]", From: "c03111", To: "d03114"},
+		{Kind: ChangeAdded, Path: "misc[Zzz]", To: "new"},
+		{Kind: ChangeModified, Path: "masks.online", From: "", To: "1-4"},
+		{Kind: ChangeRemoved, Path: "security.vulnerabilities[spectre_v1]"},
+		{Kind: ChangeModified, Path: "security.vulnerabilities[spectre_v2].status", From: "Vulnerable", To: "Mitigation"},
+	}
+	got := Diff(a, b)
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+
+	got = DiffOptions{FreqTolerance: -1}.Diff(a, b)
+	if len(got) != len(want)+1 {
+		t.Fatalf("expected %d changes, got %v", len(want)+1, got)
+	}
+}
+
+func TestChangeString(t *testing.T) {
+	for _, tc := range []struct {
+		c    Change
+		want string
+	}{
+		{Change{Kind: ChangeModified, Path: "cpus[0].microcode_version", From: "240", To: "248"}, "~ cpus[0].microcode_version: 240 -> 248"},
+		{Change{Kind: ChangeAdded, Path: "cpus[0].features", To: "avx512f"}, "+ cpus[0].features: avx512f"},
+		{Change{Kind: ChangeRemoved, Path: "cpus[0].features", From: "pti"}, "- cpus[0].features: pti"},
+		{Change{Kind: ChangeAdded, Path: "cpus[4]"}, "+ cpus[4]"},
+	} {
+		if got := tc.c.String(); got != tc.want {
+			t.Fatalf("expected %q, got %q", tc.want, got)
+		}
+	}
+}
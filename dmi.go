@@ -0,0 +1,235 @@
+package sysinfo
+
+import (
+	"errors"
+	"io/fs"
+	"strings"
+)
+
+// System describes the host's hardware identity.
+//
+// On Linux, this information is read from /sys/class/dmi/id,
+// which the kernel populates from the SMBIOS (DMI) tables.
+//
+// Each field has a "Matches:" comment describing the file
+// used.
+type System struct {
+	// Vendor is the system manufacturer.
+	//
+	// Matches: sys_vendor
+	Vendor string `json:"vendor,omitempty"`
+	// Product is the product name.
+	//
+	// Matches: product_name
+	Product string `json:"product,omitempty"`
+	// Version is the product version.
+	//
+	// Matches: product_version
+	Version string `json:"version,omitempty"`
+	// Family is the product family.
+	//
+	// Matches: product_family
+	Family string `json:"family,omitempty"`
+	// SKU is the product's stock keeping unit.
+	//
+	// Matches: product_sku
+	SKU string `json:"sku,omitempty"`
+	// Serial is the product serial number.
+	//
+	// Usually only readable by root.
+	//
+	// Matches: product_serial
+	Serial string `json:"serial,omitempty"`
+	// UUID is the product UUID.
+	//
+	// Usually only readable by root.
+	//
+	// Matches: product_uuid
+	UUID string `json:"uuid,omitempty"`
+	// Board is the system's baseboard (motherboard).
+	Board BaseBoard `json:"board"`
+	// BIOS is the system's firmware.
+	BIOS BIOS `json:"bios"`
+	// Chassis is the system's enclosure.
+	Chassis Chassis `json:"chassis"`
+	// Unavailable lists the files that exist but could not be
+	// read, usually because they require root.
+	Unavailable []string `json:"unavailable,omitempty"`
+}
+
+// BaseBoard describes a system's baseboard.
+type BaseBoard struct {
+	// Vendor is the board manufacturer.
+	//
+	// Matches: board_vendor
+	Vendor string `json:"vendor,omitempty"`
+	// Name is the board's product name.
+	//
+	// Matches: board_name
+	Name string `json:"name,omitempty"`
+	// Version is the board version.
+	//
+	// Matches: board_version
+	Version string `json:"version,omitempty"`
+	// Serial is the board serial number.
+	//
+	// Usually only readable by root.
+	//
+	// Matches: board_serial
+	Serial string `json:"serial,omitempty"`
+	// AssetTag is the board's asset tag.
+	//
+	// Matches: board_asset_tag
+	AssetTag string `json:"asset_tag,omitempty"`
+}
+
+// BIOS describes a system's firmware.
+type BIOS struct {
+	// Vendor is the firmware vendor.
+	//
+	// Matches: bios_vendor
+	Vendor string `json:"vendor,omitempty"`
+	// Version is the firmware version.
+	//
+	// Matches: bios_version
+	Version string `json:"version,omitempty"`
+	// Date is the firmware release date, usually in the form
+	// MM/DD/YYYY.
+	//
+	// Matches: bios_date
+	Date string `json:"date,omitempty"`
+	// Release is the firmware's major and minor release.
+	//
+	// Matches: bios_release
+	Release string `json:"release,omitempty"`
+}
+
+// Chassis describes a system's enclosure.
+type Chassis struct {
+	// Type is the SMBIOS chassis type.
+	//
+	// Matches: chassis_type
+	Type ChassisType `json:"type,omitempty"`
+	// Vendor is the chassis manufacturer.
+	//
+	// Matches: chassis_vendor
+	Vendor string `json:"vendor,omitempty"`
+	// Version is the chassis version.
+	//
+	// Matches: chassis_version
+	Version string `json:"version,omitempty"`
+	// Serial is the chassis serial number.
+	//
+	// Usually only readable by root.
+	//
+	// Matches: chassis_serial
+	Serial string `json:"serial,omitempty"`
+	// AssetTag is the chassis asset tag.
+	//
+	// Matches: chassis_asset_tag
+	AssetTag string `json:"asset_tag,omitempty"`
+}
+
+// ChassisType is an SMBIOS chassis type.
+//
+// See the SMBIOS specification, section 7.4.1.
+type ChassisType uint8
+
+const (
+	ChassisOther             ChassisType = 0x01 // Other
+	ChassisUnknown           ChassisType = 0x02 // Unknown
+	ChassisDesktop           ChassisType = 0x03 // Desktop
+	ChassisLowProfileDesktop ChassisType = 0x04 // Low Profile Desktop
+	ChassisPizzaBox          ChassisType = 0x05 // Pizza Box
+	ChassisMiniTower         ChassisType = 0x06 // Mini Tower
+	ChassisTower             ChassisType = 0x07 // Tower
+	ChassisPortable          ChassisType = 0x08 // Portable
+	ChassisLaptop            ChassisType = 0x09 // Laptop
+	ChassisNotebook          ChassisType = 0x0a // Notebook
+	ChassisHandHeld          ChassisType = 0x0b // Hand Held
+	ChassisDockingStation    ChassisType = 0x0c // Docking Station
+	ChassisAllInOne          ChassisType = 0x0d // All in One
+	ChassisSubNotebook       ChassisType = 0x0e // Sub Notebook
+	ChassisSpaceSaving       ChassisType = 0x0f // Space-saving
+	ChassisLunchBox          ChassisType = 0x10 // Lunch Box
+	ChassisMainServer        ChassisType = 0x11 // Main Server Chassis
+	ChassisExpansion         ChassisType = 0x12 // Expansion Chassis
+	ChassisSubChassis        ChassisType = 0x13 // SubChassis
+	ChassisBusExpansion      ChassisType = 0x14 // Bus Expansion Chassis
+	ChassisPeripheral        ChassisType = 0x15 // Peripheral Chassis
+	ChassisRAID              ChassisType = 0x16 // RAID Chassis
+	ChassisRackMount         ChassisType = 0x17 // Rack Mount Chassis
+	ChassisSealedCasePC      ChassisType = 0x18 // Sealed-case PC
+	ChassisMultiSystem       ChassisType = 0x19 // Multi-system chassis
+	ChassisCompactPCI        ChassisType = 0x1a // Compact PCI
+	ChassisAdvancedTCA       ChassisType = 0x1b // Advanced TCA
+	ChassisBlade             ChassisType = 0x1c // Blade
+	ChassisBladeEnclosure    ChassisType = 0x1d // Blade Enclosure
+	ChassisTablet            ChassisType = 0x1e // Tablet
+	ChassisConvertible       ChassisType = 0x1f // Convertible
+	ChassisDetachable        ChassisType = 0x20 // Detachable
+	ChassisIoTGateway        ChassisType = 0x21 // IoT Gateway
+	ChassisEmbeddedPC        ChassisType = 0x22 // Embedded PC
+	ChassisMiniPC            ChassisType = 0x23 // Mini PC
+	ChassisStickPC           ChassisType = 0x24 // Stick PC
+)
+
+func (t ChassisType) MarshalText() ([]byte, error) {
+	return []byte(t.String()), nil
+}
+
+func (t *ChassisType) UnmarshalText(text []byte) error {
+	v, err := parseEnum("ChassisType", string(text), func(i uint8) string {
+		return ChassisType(i).String()
+	})
+	if err != nil {
+		return err
+	}
+	*t = ChassisType(v)
+	return nil
+}
+
+// readDMI reads /sys/class/dmi/id.
+//
+// fsys should be rooted at "/". Missing files are ignored.
+func readDMI(fsys fs.FS) System {
+	var s System
+	read := func(name string) string {
+		buf, err := fs.ReadFile(fsys, "sys/class/dmi/id/"+name)
+		if err != nil {
+			if errors.Is(err, fs.ErrPermission) {
+				s.Unavailable = append(s.Unavailable, name)
+			}
+			return ""
+		}
+		return strings.TrimSpace(string(buf))
+	}
+	s.Vendor = read("sys_vendor")
+	s.Product = read("product_name")
+	s.Version = read("product_version")
+	s.Family = read("product_family")
+	s.SKU = read("product_sku")
+	s.Serial = read("product_serial")
+	s.UUID = read("product_uuid")
+	s.Board = BaseBoard{
+		Vendor:   read("board_vendor"),
+		Name:     read("board_name"),
+		Version:  read("board_version"),
+		Serial:   read("board_serial"),
+		AssetTag: read("board_asset_tag"),
+	}
+	s.BIOS = BIOS{
+		Vendor:  read("bios_vendor"),
+		Version: read("bios_version"),
+		Date:    read("bios_date"),
+		Release: read("bios_release"),
+	}
+	s.Chassis = Chassis{
+		Type:     ChassisType(atoi(read("chassis_type"))),
+		Vendor:   read("chassis_vendor"),
+		Version:  read("chassis_version"),
+		Serial:   read("chassis_serial"),
+		AssetTag: read("chassis_asset_tag"),
+	}
+	return s
+}
@@ -0,0 +1,80 @@
+package sysinfo
+
+import (
+	"io/fs"
+	"reflect"
+	"testing"
+	"testing/fstest"
+)
+
+// permFS is an fs.FS that denies access to some files.
+type permFS struct {
+	fs.FS
+	denied map[string]bool
+}
+
+func (p permFS) Open(name string) (fs.File, error) {
+	if p.denied[name] {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrPermission}
+	}
+	return p.FS.Open(name)
+}
+
+func TestReadDMI(t *testing.T) {
+	const dir = "sys/class/dmi/id/"
+	files := map[string]string{
+		"sys_vendor":      "QEMU",
+		"product_name":    "Standard PC (Q35 + ICH9, 2009)",
+		"product_version": "pc-q35-5.2",
+		"product_serial":  "secret",
+		"product_uuid":    "secret",
+		"board_vendor":    "Supermicro",
+		"board_name":      "H11DSi-NT",
+		"board_version":   "2.00",
+		"board_serial":    "secret",
+		"bios_vendor":     "American Megatrends Inc.",
+		"bios_version":    "2.1",
+		"bios_date":       "06/14/2019",
+		"bios_release":    "5.14",
+		"chassis_type":    "17",
+		"chassis_vendor":  "Supermicro",
+	}
+	mfs := make(fstest.MapFS)
+	for k, v := range files {
+		mfs[dir+k] = &fstest.MapFile{Data: []byte(v + "\n")}
+	}
+	fsys := permFS{FS: mfs, denied: map[string]bool{
+		dir + "product_serial": true,
+		dir + "product_uuid":   true,
+		dir + "board_serial":   true,
+	}}
+
+	want := System{
+		Vendor:  "QEMU",
+		Product: "Standard PC (Q35 + ICH9, 2009)",
+		Version: "pc-q35-5.2",
+		Board: BaseBoard{
+			Vendor:  "Supermicro",
+			Name:    "H11DSi-NT",
+			Version: "2.00",
+		},
+		BIOS: BIOS{
+			Vendor:  "American Megatrends Inc.",
+			Version: "2.1",
+			Date:    "06/14/2019",
+			Release: "5.14",
+		},
+		Chassis: Chassis{
+			Type:   ChassisMainServer,
+			Vendor: "Supermicro",
+		},
+		Unavailable: []string{"product_serial", "product_uuid", "board_serial"},
+	}
+	got := readDMI(fsys)
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %#v, got %#v", want, got)
+	}
+	if s := got.Chassis.Type.String(); s != "Main Server Chassis" {
+		t.Fatalf("unexpected chassis type: %q", s)
+	}
+}
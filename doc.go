@@ -5,3 +5,11 @@
 package sysinfo
 
 //go:generate go run golang.org/x/tools/cmd/stringer -type Implementer -linecomment
+//go:generate go run golang.org/x/tools/cmd/stringer -type ChassisType -linecomment
+//go:generate go run golang.org/x/tools/cmd/stringer -type MemoryType -linecomment
+//go:generate go run golang.org/x/tools/cmd/stringer -type MemoryFormFactor -linecomment
+//go:generate go run golang.org/x/tools/cmd/stringer -type MemoryECC -linecomment
+//go:generate go run golang.org/x/tools/cmd/stringer -type SlotUsage -linecomment
+//go:generate go run golang.org/x/tools/cmd/stringer -type VulnStatus -linecomment
+//go:generate go run golang.org/x/tools/cmd/stringer -type ChangeKind -linecomment
+//go:generate go test -run TestJSONSchema$ -update
@@ -0,0 +1,131 @@
+package sysinfo
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Fingerprint returns a stable identity for the host's
+// hardware.
+//
+// The sum is the hex-encoded SHA-256 hash of canonical, which
+// is a human-readable list of "key=value" lines describing
+// the hardware. Comparing the canonical forms of two hosts
+// explains why their sums differ.
+//
+// The fingerprint includes each CPU's vendor, family, model,
+// stepping, part, variant, model name, features, and cache
+// sizes, the CPU topology, and the system's vendor and
+// product. It excludes values that vary from moment to
+// moment or between identical hosts, such as the frequency,
+// BogoMIPS, microcode version, bugs, idle governor, and
+// serial numbers.
+//
+// Only online CPUs are included, since /proc/cpuinfo does not
+// describe offline CPUs, so taking CPUs offline changes the
+// fingerprint. The topology is counted like LSCPU: x86 CPUs
+// report their packages and cores, and other CPUs are
+// assumed to have one thread per core.
+//
+// CPUs with identical descriptions are grouped together, so
+// the canonical form looks like
+//
+//	cpus[0-3].implementer=ARM Ltd
+//	cpus[0-3].part_number=0xd08
+//	topology.cpus=4
+func (v Info) Fingerprint() (sum, canonical string) {
+	var lines fingerprintLines
+	add := lines.add
+
+	add("system.vendor", v.System.Vendor)
+	add("system.product", v.System.Product)
+	add("system.board.vendor", v.System.Board.Vendor)
+	add("system.board.name", v.System.Board.Name)
+	add("board.model", v.Board.Model)
+	add("board.soc", v.Board.SoC)
+
+	cpus := v.onlineCPUs()
+	var (
+		groups []string
+		sets   = make(map[string]*CPUSet)
+	)
+	for _, c := range cpus {
+		desc := strings.Join(cpuFingerprint(c), "\n")
+		set, ok := sets[desc]
+		if !ok {
+			set = new(CPUSet)
+			sets[desc] = set
+			groups = append(groups, desc)
+		}
+		set.Set(c.Proc)
+	}
+	for _, desc := range groups {
+		prefix := "cpus[" + sets[desc].String() + "]."
+		for _, line := range strings.Split(desc, "\n") {
+			if line != "" {
+				lines = append(lines, prefix+line)
+			}
+		}
+	}
+
+	add("topology.cpus", len(cpus))
+	if t := v.lscpuTopology(); t.sockets > 0 {
+		if cpus[0].VendorID != "" {
+			add("topology.packages", t.sockets)
+		}
+		add("topology.cores", t.cores*t.sockets)
+	}
+
+	canonical = strings.Join(lines, "\n") + "\n"
+	h := sha256.Sum256([]byte(canonical))
+	return hex.EncodeToString(h[:]), canonical
+}
+
+// cpuFingerprint returns the fingerprint lines that describe
+// a single CPU, without the "cpus[N]." prefix.
+func cpuFingerprint(c CPU) []string {
+	var lines fingerprintLines
+	add := lines.add
+	add("vendor_id", c.VendorID)
+	if c.Impl != 0 {
+		add("implementer", c.Impl)
+	}
+	add("arch", c.Arch)
+	add("variant", c.Variant)
+	if c.Part != 0 {
+		add("part_number", fmt.Sprintf("%#x", uint16(c.Part)))
+	}
+	add("family", c.Family)
+	add("model_number", c.Model)
+	add("revision", c.Rev)
+	add("model_name", c.ModelName)
+	add("micro_arch", c.MicroArch)
+	add("num_cores", c.Cores)
+	add("siblings", c.Siblings)
+	add("cache.instruction", c.Cache.Inst)
+	add("cache.l1", c.Cache.L1)
+	add("cache.l2", c.Cache.L2)
+	add("cache.l3", c.Cache.L3)
+	add("cache.alignment", c.Cache.Alignment)
+	add("address_sizes.physical_bits", c.AddrSizes.Phys)
+	add("address_sizes.virtual_bits", c.AddrSizes.Virt)
+	if len(c.Features) > 0 {
+		feats := append([]string(nil), c.Features...)
+		sort.Strings(feats)
+		add("features", strings.Join(feats, " "))
+	}
+	return lines
+}
+
+type fingerprintLines []string
+
+// add adds a "key=value" line unless val is empty or zero.
+func (l *fingerprintLines) add(key string, val interface{}) {
+	s := fmt.Sprint(val)
+	if s != "" && s != "0" {
+		*l = append(*l, key+"="+s)
+	}
+}
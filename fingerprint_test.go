@@ -0,0 +1,76 @@
+package sysinfo
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFingerprint(t *testing.T) {
+	a := readTestInfo(t, "raspberry_pi_4b")
+	sum, canon := a.Fingerprint()
+	want := `board.model=Raspberry Pi 4 Model B Rev 1.1
+board.soc=BCM2711
+cpus[0-3].implementer=ARM Ltd
+cpus[0-3].arch=7
+cpus[0-3].part_number=0xd08
+cpus[0-3].revision=3
+cpus[0-3].model_name=ARMv7 Processor rev 3 (v7l)
+cpus[0-3].features=crc32 edsp evtstrm fastmult half idiva idivt lpae neon thumb tls vfp vfpd32 vfpv3 vfpv4
+topology.cpus=4
+topology.cores=4
+`
+	if canon != want {
+		t.Fatalf("expected %q, got %q", want, canon)
+	}
+
+	// Volatile values and feature order do not matter.
+	b := readTestInfo(t, "raspberry_pi_4b")
+	for i := range b.CPUs {
+		b.CPUs[i].Freq = 1500
+		b.CPUs[i].BogoMIPS++
+		b.CPUs[i].Microcode = 0xf0
+		f := b.CPUs[i].Features
+		f[0], f[len(f)-1] = f[len(f)-1], f[0]
+	}
+	b.CPUIdle.Governor = "teo"
+	if got, _ := b.Fingerprint(); got != sum {
+		t.Fatalf("expected %s, got %s", sum, got)
+	}
+
+	// A different core changes the fingerprint.
+	b.CPUs[3].Part = CortexA53
+	got, canon := b.Fingerprint()
+	if got == sum {
+		t.Fatal("expected a different fingerprint")
+	}
+	if !strings.Contains(canon, "cpus[0-2].part_number=0xd08\n") ||
+		!strings.Contains(canon, "cpus[3].part_number=0xd03\n") {
+		t.Fatalf("unexpected canonical form: %q", canon)
+	}
+}
+
+func TestFingerprintTopology(t *testing.T) {
+	a := readTestInfo(t, "amd_epyc_ubuntu")
+	_, canon := a.Fingerprint()
+	if !strings.Contains(canon, "topology.packages=1\n") {
+		t.Fatalf("unexpected canonical form: %q", canon)
+	}
+
+	// Offline CPUs are not described and do not hide the
+	// topology.
+	var b Info
+	for i := 0; i < 4; i++ {
+		b.CPUs = append(b.CPUs, CPU{Proc: i, VendorID: "GenuineIntel", CoreID: i % 2})
+	}
+	b.Masks.Present = NewCPUSet(0, 1, 2, 3, 4, 5)
+	b.Masks.Online = NewCPUSet(0, 1, 2, 3)
+	applyCPUMasks(&b)
+	_, canon = b.Fingerprint()
+	const want = "cpus[0-3].vendor_id=GenuineIntel\n" +
+		"topology.cpus=4\n" +
+		"topology.packages=1\n" +
+		"topology.cores=2\n"
+	if canon != want {
+		t.Fatalf("expected %q, got %q", want, canon)
+	}
+}
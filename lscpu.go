@@ -0,0 +1,465 @@
+package sysinfo
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+)
+
+// LSCPUOptions configures WriteLSCPU.
+type LSCPUOptions struct {
+	// Extended writes a table with a row per CPU, like
+	// "lscpu -e", instead of the summary.
+	Extended bool
+	// JSON writes JSON, like "lscpu -J".
+	JSON bool
+}
+
+// WriteLSCPU writes v to w in the format of lscpu(1) from
+// util-linux 2.34, for hosts that do not have lscpu.
+//
+// The summary is written as "Label: value" lines, or, with
+// JSON, as
+//
+//	{"lscpu": [{"field": "Architecture:", "data": "x86_64"}, ...]}
+//
+// The extended table has the columns CPU, NODE (if the host
+// has NUMA nodes), SOCKET, CORE, the cache IDs, ONLINE, and
+// MHZ (if known), or, with JSON, is written as
+//
+//	{"cpus": [{"cpu": "0", "node": "0", ...}, ...]}
+//
+// See Info.LSCPU for how the values are derived.
+func (v Info) WriteLSCPU(w io.Writer, opts LSCPUOptions) error {
+	if opts.Extended {
+		return v.writeLSCPUExtended(w, opts.JSON)
+	}
+	fields := v.LSCPU()
+	if opts.JSON {
+		objs := make([][]Pair, len(fields))
+		for i, f := range fields {
+			objs[i] = []Pair{{"field", f.Key + ":"}, {"data", f.Value}}
+		}
+		return writeLSCPUJSON(w, "lscpu", objs)
+	}
+	width := 0
+	for _, f := range fields {
+		if n := len(f.Key) + 1; n > width {
+			width = n
+		}
+	}
+	for _, f := range fields {
+		if _, err := fmt.Fprintf(w, "%-*s %s\n", width, f.Key+":", f.Value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// LSCPU returns the summary that lscpu(1) prints as a list of
+// labels and values, for example
+//
+//	{"Architecture", "x86_64"}
+//	{"CPU(s)", "8"}
+//
+// Values that are not known are omitted.
+//
+// Only online CPUs are described, since /proc/cpuinfo does
+// not list offline CPUs. The topology of x86 CPUs is derived
+// from their physical and core IDs. Other CPUs are assumed to
+// have a single socket and one thread per core. Each distinct
+// Arm core type, such as the big and LITTLE cores, has its
+// own model lines. Cache sizes are the total across the host,
+// assuming that L1 and L2 caches are private to a core and
+// L3 caches are shared by a socket.
+func (v Info) LSCPU() []Pair {
+	var (
+		fields []Pair
+		add    = func(key, value string) {
+			if value != "" {
+				fields = append(fields, Pair{Key: key, Value: value})
+			}
+		}
+		addInt = func(key string, value int) {
+			if value != 0 {
+				add(key, strconv.Itoa(value))
+			}
+		}
+	)
+	cpus := v.onlineCPUs()
+	if len(cpus) == 0 {
+		return nil
+	}
+	c := cpus[0]
+	arch := machine(c)
+	add("Architecture", arch)
+	add("CPU op-mode(s)", opModes(arch))
+	if arch != "" {
+		add("Byte Order", "Little Endian")
+	}
+	if c.AddrSizes.Phys != 0 && c.AddrSizes.Virt != 0 {
+		add("Address sizes", fmt.Sprintf("%d bits physical, %d bits virtual",
+			c.AddrSizes.Phys, c.AddrSizes.Virt))
+	}
+
+	present, online := v.lscpuMasks()
+	addInt("CPU(s)", present.Len())
+	add("On-line CPU(s) list", online.String())
+	if off := present.Difference(online); !off.IsEmpty() {
+		add("Off-line CPU(s) list", off.String())
+	}
+	t := v.lscpuTopology()
+	addInt("Thread(s) per core", t.threads)
+	addInt("Core(s) per socket", t.cores)
+	addInt("Socket(s)", t.sockets)
+	addInt("NUMA node(s)", len(v.NUMA))
+
+	for _, c := range lscpuModels(cpus) {
+		if c.VendorID != "" {
+			add("Vendor ID", c.VendorID)
+			addInt("CPU family", c.Family)
+			add("Model", strconv.Itoa(c.Model))
+			add("Model name", c.ModelName)
+			add("Stepping", strconv.Itoa(c.Rev))
+		} else {
+			add("Vendor ID", lscpuVendor(c.Impl))
+			add("Model", strconv.Itoa(c.Rev))
+			name := c.Name()
+			if name == "generic" {
+				name = "-"
+			}
+			add("Model name", name)
+			add("Stepping", fmt.Sprintf("r%dp%d", c.Variant, c.Rev))
+		}
+		if c.Freq != 0 {
+			add("CPU MHz", strconv.FormatFloat(c.Freq, 'f', 3, 64))
+		}
+		if c.BogoMIPS != 0 {
+			add("BogoMIPS", strconv.FormatFloat(c.BogoMIPS, 'f', 2, 64))
+		}
+	}
+
+	cores := t.cores * t.sockets
+	add("L1d cache", lscpuSize(c.Cache.L1, cores))
+	add("L1i cache", lscpuSize(c.Cache.Inst, cores))
+	add("L2 cache", lscpuSize(c.Cache.L2, cores))
+	add("L3 cache", lscpuSize(c.Cache.L3, t.sockets))
+	for _, n := range v.NUMA {
+		add(fmt.Sprintf("NUMA node%d CPU(s)", n.ID), n.CPUs.String())
+	}
+	for _, vuln := range v.Security.Vulnerabilities {
+		add("Vulnerability "+lscpuVulnName(vuln.Name), vuln.Raw)
+	}
+	add("Flags", strings.Join(c.Features, " "))
+	return fields
+}
+
+// writeLSCPUExtended writes the "lscpu -e" table.
+func (v Info) writeLSCPUExtended(w io.Writer, asJSON bool) error {
+	levels := []struct {
+		name string
+		size func(Cache) int
+	}{
+		{"L1d", func(c Cache) int { return c.L1 }},
+		{"L1i", func(c Cache) int { return c.Inst }},
+		{"L2", func(c Cache) int { return c.L2 }},
+		{"L3", func(c Cache) int { return c.L3 }},
+	}
+	var caches []string
+	hasFreq := false
+	for _, l := range levels {
+		for _, c := range v.CPUs {
+			if l.size(c.Cache) != 0 {
+				caches = append(caches, l.name)
+				break
+			}
+		}
+	}
+	for _, c := range v.CPUs {
+		hasFreq = hasFreq || c.Freq != 0
+	}
+
+	header := []string{"CPU"}
+	if len(v.NUMA) > 0 {
+		header = append(header, "NODE")
+	}
+	header = append(header, "SOCKET", "CORE")
+	if len(caches) > 0 {
+		header = append(header, strings.Join(caches, ":"))
+	}
+	header = append(header, "ONLINE")
+	if hasFreq {
+		header = append(header, "MHZ")
+	}
+
+	t := v.lscpuTopology()
+	_, online := v.lscpuMasks()
+	rows := make([][]string, 0, len(v.CPUs))
+	for _, c := range v.CPUs {
+		row := []string{strconv.Itoa(c.Proc)}
+		if len(v.NUMA) > 0 {
+			node := "-"
+			for _, n := range v.NUMA {
+				if n.CPUs.Has(c.Proc) {
+					node = strconv.Itoa(n.ID)
+					break
+				}
+			}
+			row = append(row, node)
+		}
+		if !online.Has(c.Proc) {
+			// Like lscpu, offline CPUs have no topology.
+			row = append(row, "-", "-")
+			if len(caches) > 0 {
+				row = append(row, "-")
+			}
+			row = append(row, "no")
+			if hasFreq {
+				row = append(row, "-")
+			}
+			rows = append(rows, row)
+			continue
+		}
+		socket, core := t.socket[c.Proc], t.core[c.Proc]
+		row = append(row, strconv.Itoa(socket), strconv.Itoa(core))
+		if len(caches) > 0 {
+			// L1 and L2 caches are assumed to be private to a
+			// core and L3 caches shared by a socket.
+			ids := make([]string, len(caches))
+			for i, name := range caches {
+				if name == "L3" {
+					ids[i] = strconv.Itoa(socket)
+				} else {
+					ids[i] = strconv.Itoa(core)
+				}
+			}
+			row = append(row, strings.Join(ids, ":"))
+		}
+		row = append(row, "yes")
+		if hasFreq {
+			row = append(row, strconv.FormatFloat(c.Freq, 'f', 4, 64))
+		}
+		rows = append(rows, row)
+	}
+
+	if asJSON {
+		objs := make([][]Pair, len(rows))
+		for i, row := range rows {
+			objs[i] = make([]Pair, len(header))
+			for j, col := range header {
+				objs[i][j] = Pair{strings.ToLower(col), row[j]}
+			}
+		}
+		return writeLSCPUJSON(w, "cpus", objs)
+	}
+	tw := tabwriter.NewWriter(w, 0, 8, 1, ' ', 0)
+	fmt.Fprintln(tw, strings.Join(header, "\t"))
+	for _, row := range rows {
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+	return tw.Flush()
+}
+
+// writeLSCPUJSON writes a JSON object with a single member,
+// name, that is a list of objects. Like lscpu, each object is
+// written on its own line with its members in order.
+func writeLSCPUJSON(w io.Writer, name string, objs [][]Pair) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "{\n   %q: [", name)
+	for i, obj := range objs {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString("\n      {")
+		for j, p := range obj {
+			if j > 0 {
+				b.WriteString(", ")
+			}
+			k, _ := json.Marshal(p.Key)
+			v, _ := json.Marshal(p.Value)
+			fmt.Fprintf(&b, "%s: %s", k, v)
+		}
+		b.WriteByte('}')
+	}
+	b.WriteString("\n   ]\n}\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// lscpuMasks returns the present and online CPUs.
+//
+// If the masks were not read, the CPUs in /proc/cpuinfo are
+// assumed to be online, since it only lists online CPUs.
+func (v Info) lscpuMasks() (present, online CPUSet) {
+	if !v.Masks.Present.IsEmpty() && !v.Masks.Online.IsEmpty() {
+		return v.Masks.Present, v.Masks.Online
+	}
+	for _, c := range v.CPUs {
+		present.Set(c.Proc)
+	}
+	return present, present.Clone()
+}
+
+type lscpuTopology struct {
+	threads int
+	cores   int
+	sockets int
+	// socket and core map each CPU to its socket and logical
+	// core index.
+	socket map[int]int
+	core   map[int]int
+}
+
+// lscpuTopology returns the topology of the online CPUs as
+// lscpu reports it.
+//
+// Threads per core is the largest number of online threads
+// in any core.
+func (v Info) lscpuTopology() lscpuTopology {
+	cpus := v.onlineCPUs()
+	t := lscpuTopology{
+		socket: make(map[int]int, len(cpus)),
+		core:   make(map[int]int, len(cpus)),
+	}
+	if len(cpus) == 0 {
+		return t
+	}
+	x86 := cpus[0].VendorID != ""
+	type coreKey struct{ pkg, id int }
+	var (
+		sockets = make(map[int]int)
+		cores   = make(map[coreKey]int)
+		threads = make(map[coreKey]int)
+	)
+	for _, c := range cpus {
+		key := coreKey{0, c.Proc}
+		if x86 {
+			key = coreKey{c.PhysID, c.CoreID}
+		}
+		if _, ok := sockets[key.pkg]; !ok {
+			sockets[key.pkg] = len(sockets)
+		}
+		if _, ok := cores[key]; !ok {
+			cores[key] = len(cores)
+		}
+		t.socket[c.Proc] = sockets[key.pkg]
+		t.core[c.Proc] = cores[key]
+		threads[key]++
+		if threads[key] > t.threads {
+			t.threads = threads[key]
+		}
+	}
+	t.sockets = len(sockets)
+	t.cores = len(cores) / t.sockets
+	return t
+}
+
+// lscpuModels returns the first CPU of each distinct model.
+func lscpuModels(cpus []CPU) []CPU {
+	type model struct {
+		vendor        string
+		family, model int
+		impl          Implementer
+		part          Part
+		variant, rev  int
+	}
+	var (
+		seen   = make(map[model]bool)
+		models []CPU
+	)
+	for _, c := range cpus {
+		m := model{c.VendorID, c.Family, c.Model, c.Impl, c.Part, c.Variant, c.Rev}
+		if !seen[m] {
+			seen[m] = true
+			models = append(models, c)
+		}
+	}
+	return models
+}
+
+// machine returns the CPU's machine hardware name, as printed
+// by uname -m.
+func machine(c CPU) string {
+	switch {
+	case c.VendorID != "":
+		if hasString(c.Features, "lm") {
+			return "x86_64"
+		}
+		return "i686"
+	case c.Impl != 0:
+		// 32-bit kernels on ARMv8 CPUs report the CPU as
+		// ARMv7, for example "ARMv7 Processor rev 3 (v7l)".
+		if c.Arch >= 8 && !strings.Contains(c.ModelName, "(v7l)") {
+			return "aarch64"
+		}
+		if c.Arch != 0 {
+			return fmt.Sprintf("armv%dl", c.Arch)
+		}
+	}
+	return ""
+}
+
+func opModes(arch string) string {
+	switch arch {
+	case "x86_64":
+		return "32-bit, 64-bit"
+	case "aarch64":
+		return "64-bit"
+	case "":
+		return ""
+	default:
+		return "32-bit"
+	}
+}
+
+func hasString(list []string, s string) bool {
+	for _, x := range list {
+		if x == s {
+			return true
+		}
+	}
+	return false
+}
+
+// lscpuVendor returns the implementer's name without its
+// corporate suffix, for example "ARM" for "ARM Ltd".
+func lscpuVendor(impl Implementer) string {
+	s := impl.String()
+	for _, suffix := range []string{" Ltd", " Corporation", " Inc"} {
+		s = strings.TrimSuffix(s, suffix)
+	}
+	return s
+}
+
+// lscpuSize formats the total size of count caches of n
+// bytes each, for example "128 KiB" or "27.5 MiB".
+func lscpuSize(n, count int) string {
+	if n == 0 || count == 0 {
+		return ""
+	}
+	total := uint64(n) * uint64(count)
+	exp := 0
+	for exp < 60 && total >= 1<<(exp+10) {
+		exp += 10
+	}
+	if exp == 0 {
+		return strconv.FormatUint(total, 10) + " B"
+	}
+	v := math.Round(float64(total)/float64(uint64(1)<<exp)*10) / 10
+	return strconv.FormatFloat(v, 'f', -1, 64) + " " + "KMGTPE"[exp/10-1:exp/10] + "iB"
+}
+
+// lscpuVulnName returns the name lscpu uses for a
+// vulnerability, for example "Spec store bypass" for
+// "spec_store_bypass".
+func lscpuVulnName(name string) string {
+	s := strings.ReplaceAll(name, "_", " ")
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
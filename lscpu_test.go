@@ -0,0 +1,209 @@
+package sysinfo
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestLSCPU(t *testing.T) {
+	v := readTestInfo(t, "raspberry_pi_4b")
+	v.Security.Vulnerabilities = []Vulnerability{
+		{Name: "spec_store_bypass", Status: VulnVulnerable, Raw: "Vulnerable"},
+		{Name: "spectre_v1", Status: VulnMitigated, Detail: "__user pointer sanitization", Raw: "Mitigation: __user pointer sanitization"},
+	}
+	var buf bytes.Buffer
+	if err := v.WriteLSCPU(&buf, LSCPUOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	const want = `Architecture:                    armv7l
+CPU op-mode(s):                  32-bit
+Byte Order:                      Little Endian
+CPU(s):                          4
+On-line CPU(s) list:             0-3
+Thread(s) per core:              1
+Core(s) per socket:              4
+Socket(s):                       1
+Vendor ID:                       ARM
+Model:                           3
+Model name:                      Cortex-A72
+Stepping:                        r0p3
+BogoMIPS:                        108.00
+Vulnerability Spec store bypass: Vulnerable
+Vulnerability Spectre v1:        Mitigation: __user pointer sanitization
+Flags:                           half thumb fastmult vfp edsp neon vfpv3 tls vfpv4 idiva idivt vfpd32 lpae evtstrm crc32
+`
+	if got := buf.String(); got != want {
+		t.Fatalf("expected\n%s\ngot\n%s", want, got)
+	}
+}
+
+// lscpuTestInfo returns a host with two sockets, each with
+// two cores with two threads.
+func lscpuTestInfo() Info {
+	var v Info
+	for i := 0; i < 8; i++ {
+		v.CPUs = append(v.CPUs, CPU{
+			Proc:     i,
+			VendorID: "GenuineIntel",
+			Features: []string{"fpu", "lm"},
+			PhysID:   i / 4,
+			CoreID:   i % 2,
+			Freq:     2000,
+			Cache:    Cache{L1: 32 << 10, Inst: 32 << 10, L2: 1 << 20, L3: 32 << 20},
+		})
+	}
+	v.Masks.Present = NewCPUSet(0, 1, 2, 3, 4, 5, 6, 7)
+	v.Masks.Online = NewCPUSet(0, 1, 2, 3, 4, 5, 6)
+	v.NUMA = []NUMANode{
+		{ID: 0, CPUs: NewCPUSet(0, 1, 2, 3)},
+		{ID: 1, CPUs: NewCPUSet(4, 5, 6, 7)},
+	}
+	return v
+}
+
+func TestLSCPUTopology(t *testing.T) {
+	fields := make(map[string]string)
+	for _, p := range lscpuTestInfo().LSCPU() {
+		fields[p.Key] = p.Value
+	}
+	for k, want := range map[string]string{
+		"Architecture":         "x86_64",
+		"CPU(s)":               "8",
+		"On-line CPU(s) list":  "0-6",
+		"Off-line CPU(s) list": "7",
+		"Thread(s) per core":   "2",
+		"Core(s) per socket":   "2",
+		"Socket(s)":            "2",
+		"NUMA node(s)":         "2",
+		"L1d cache":            "128 KiB",
+		"L3 cache":             "64 MiB",
+		"NUMA node1 CPU(s)":    "4-7",
+	} {
+		if got := fields[k]; got != want {
+			t.Errorf("%s: expected %q, got %q", k, want, got)
+		}
+	}
+}
+
+func TestLSCPUExtended(t *testing.T) {
+	v := lscpuTestInfo()
+	v.CPUs = v.CPUs[3:6]
+
+	var buf bytes.Buffer
+	if err := v.WriteLSCPU(&buf, LSCPUOptions{Extended: true}); err != nil {
+		t.Fatal(err)
+	}
+	const want = `CPU NODE SOCKET CORE L1d:L1i:L2:L3 ONLINE MHZ
+3   0    0      0    0:0:0:0       yes    2000.0000
+4   1    1      1    1:1:1:1       yes    2000.0000
+5   1    1      2    2:2:2:1       yes    2000.0000
+`
+	if got := buf.String(); got != want {
+		t.Fatalf("expected\n%s\ngot\n%s", want, got)
+	}
+
+	buf.Reset()
+	if err := v.WriteLSCPU(&buf, LSCPUOptions{Extended: true, JSON: true}); err != nil {
+		t.Fatal(err)
+	}
+	const wantJSON = `{
+   "cpus": [
+      {"cpu": "3", "node": "0", "socket": "0", "core": "0", "l1d:l1i:l2:l3": "0:0:0:0", "online": "yes", "mhz": "2000.0000"},
+      {"cpu": "4", "node": "1", "socket": "1", "core": "1", "l1d:l1i:l2:l3": "1:1:1:1", "online": "yes", "mhz": "2000.0000"},
+      {"cpu": "5", "node": "1", "socket": "1", "core": "2", "l1d:l1i:l2:l3": "2:2:2:1", "online": "yes", "mhz": "2000.0000"}
+   ]
+}
+`
+	if got := buf.String(); got != wantJSON {
+		t.Fatalf("expected\n%s\ngot\n%s", wantJSON, got)
+	}
+}
+
+func TestLSCPUOfflinePlaceholders(t *testing.T) {
+	// Two cores with two threads each, plus two offline CPUs
+	// that, like detection, only have their number.
+	var v Info
+	for i := 0; i < 4; i++ {
+		v.CPUs = append(v.CPUs, CPU{
+			Proc:      i,
+			VendorID:  "GenuineIntel",
+			Family:    6,
+			Model:     85,
+			ModelName: "Intel(R) Xeon(R) Gold 6230 CPU @ 2.10GHz",
+			Features:  []string{"fpu", "lm"},
+			CoreID:    i % 2,
+			Cache:     Cache{L1: 32 << 10},
+		})
+	}
+	v.Masks.Present = NewCPUSet(0, 1, 2, 3, 4, 5)
+	v.Masks.Online = NewCPUSet(0, 1, 2, 3)
+	applyCPUMasks(&v)
+	if len(v.CPUs) != 6 {
+		t.Fatalf("expected 6 CPUs, got %d", len(v.CPUs))
+	}
+
+	fields := make(map[string]string)
+	vendors := 0
+	for _, p := range v.LSCPU() {
+		fields[p.Key] = p.Value
+		if p.Key == "Vendor ID" {
+			vendors++
+		}
+	}
+	for k, want := range map[string]string{
+		"CPU(s)":               "6",
+		"Off-line CPU(s) list": "4-5",
+		"Thread(s) per core":   "2",
+		"Core(s) per socket":   "2",
+		"Socket(s)":            "1",
+		"Model":                "85",
+		"L1d cache":            "64 KiB",
+	} {
+		if got := fields[k]; got != want {
+			t.Errorf("%s: expected %q, got %q", k, want, got)
+		}
+	}
+	if vendors != 1 {
+		t.Errorf("expected a single model, got %d", vendors)
+	}
+
+	var buf bytes.Buffer
+	if err := v.WriteLSCPU(&buf, LSCPUOptions{Extended: true}); err != nil {
+		t.Fatal(err)
+	}
+	const want = `CPU SOCKET CORE L1d ONLINE
+0   0      0    0   yes
+1   0      1    1   yes
+2   0      0    0   yes
+3   0      1    1   yes
+4   -      -    -   no
+5   -      -    -   no
+`
+	if got := buf.String(); got != want {
+		t.Fatalf("expected\n%s\ngot\n%s", want, got)
+	}
+
+	for _, p := range v.BenchConfig() {
+		if p.Key == "cores" && p.Value != "2" {
+			t.Fatalf("expected 2 cores, got %q", p.Value)
+		}
+	}
+}
+
+func TestLSCPUSize(t *testing.T) {
+	for _, tc := range []struct {
+		n, count int
+		want     string
+	}{
+		{0, 4, ""},
+		{512, 1, "512 B"},
+		{32 << 10, 4, "128 KiB"},
+		{256 << 10, 4, "1 MiB"},
+		{1408 << 10, 20, "27.5 MiB"},
+		{32 << 20, 2, "64 MiB"},
+	} {
+		if got := lscpuSize(tc.n, tc.count); got != tc.want {
+			t.Errorf("lscpuSize(%d, %d): expected %q, got %q", tc.n, tc.count, tc.want, got)
+		}
+	}
+}
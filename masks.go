@@ -0,0 +1,144 @@
+package sysinfo
+
+import (
+	"io/fs"
+	"sort"
+	"strings"
+)
+
+// CPUMasks describes which CPUs the kernel knows about and
+// which of them are usable.
+//
+// On Linux, this information is read from
+// /sys/devices/system/cpu and sched_getaffinity(2), or
+// /proc/self/status when read from an archive.
+type CPUMasks struct {
+	// Possible is the set of CPUs that could ever be brought
+	// online, including hotpluggable CPUs.
+	//
+	// Matches: possible
+	Possible CPUSet `json:"possible"`
+	// Present is the set of CPUs that are physically present.
+	//
+	// Matches: present
+	Present CPUSet `json:"present"`
+	// Online is the set of CPUs that are online and being
+	// scheduled.
+	//
+	// Matches: online
+	Online CPUSet `json:"online"`
+	// Offline is the set of CPUs that are not online, either
+	// because they were hotplugged off or because they exceed
+	// the limit set by the maxcpus kernel parameter.
+	//
+	// Matches: offline
+	Offline CPUSet `json:"offline"`
+	// Isolated is the set of CPUs removed from the general
+	// scheduler by the isolcpus kernel parameter.
+	//
+	// Matches: isolated
+	Isolated CPUSet `json:"isolated"`
+	// NoHZFull is the set of CPUs running in adaptive-tick
+	// mode, as set by the nohz_full kernel parameter.
+	//
+	// Matches: nohz_full
+	NoHZFull CPUSet `json:"nohz_full"`
+	// Allowed is the set of CPUs the current process is
+	// allowed to run on.
+	//
+	// Matches: Cpus_allowed_list
+	Allowed CPUSet `json:"allowed"`
+}
+
+// readCPUMasks reads the CPU lists in /sys/devices/system/cpu.
+//
+// fsys should be rooted at "/". Missing files are ignored.
+func readCPUMasks(fsys fs.FS) CPUMasks {
+	read := func(name string) CPUSet {
+		buf, err := fs.ReadFile(fsys, "sys/devices/system/cpu/"+name)
+		if err != nil {
+			return CPUSet{}
+		}
+		set, _ := ParseCPUList(string(buf))
+		return set
+	}
+	return CPUMasks{
+		Possible: read("possible"),
+		Present:  read("present"),
+		Online:   read("online"),
+		Offline:  read("offline"),
+		Isolated: read("isolated"),
+		NoHZFull: read("nohz_full"),
+	}
+}
+
+// readAllowed reads the Cpus_allowed_list line of
+// /proc/self/status.
+//
+// It is only used for archives written by Capture, since the
+// live host uses sched_getaffinity(2).
+func readAllowed(fsys fs.FS) CPUSet {
+	buf, err := fs.ReadFile(fsys, "proc/self/status")
+	if err != nil {
+		return CPUSet{}
+	}
+	for _, line := range strings.Split(string(buf), "\n") {
+		k, v := split(line)
+		if k == "Cpus_allowed_list" {
+			set, _ := ParseCPUList(v)
+			return set
+		}
+	}
+	return CPUSet{}
+}
+
+// onlineCPUs returns the online CPUs, skipping the entries
+// that applyCPUMasks adds for offline CPUs.
+//
+// If the masks were not read, every CPU is assumed to be
+// online.
+func (v Info) onlineCPUs() []CPU {
+	_, online := v.lscpuMasks()
+	cpus := make([]CPU, 0, len(v.CPUs))
+	for _, c := range v.CPUs {
+		if online.Has(c.Proc) {
+			cpus = append(cpus, c)
+		}
+	}
+	return cpus
+}
+
+// applyCPUMasks marks each CPU in o with its online,
+// isolated, and allowed state.
+//
+// CPUs that are present but not online do not appear in
+// /proc/cpuinfo, so applyCPUMasks adds them to o.CPUs.
+func applyCPUMasks(o *Info) {
+	m := &o.Masks
+	seen := make(map[int]bool, len(o.CPUs))
+	for i := range o.CPUs {
+		// Every CPU in /proc/cpuinfo is online.
+		o.CPUs[i].Online = true
+		seen[o.CPUs[i].Proc] = true
+	}
+	m.Present.Range(func(p int) bool {
+		if !seen[p] {
+			o.CPUs = append(o.CPUs, CPU{Proc: p})
+			seen[p] = true
+		}
+		return true
+	})
+	for i := range o.CPUs {
+		c := &o.CPUs[i]
+		if !m.Online.IsEmpty() {
+			c.Online = m.Online.Has(c.Proc)
+		}
+		c.Isolated = m.Isolated.Has(c.Proc)
+		if !m.Allowed.IsEmpty() {
+			c.Allowed = c.Online && m.Allowed.Has(c.Proc)
+		}
+	}
+	sort.Slice(o.CPUs, func(i, j int) bool {
+		return o.CPUs[i].Proc < o.CPUs[j].Proc
+	})
+}
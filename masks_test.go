@@ -0,0 +1,50 @@
+package sysinfo
+
+import (
+	"reflect"
+	"testing"
+	"testing/fstest"
+)
+
+func TestApplyCPUMasks(t *testing.T) {
+	fsys := fstest.MapFS{
+		"sys/devices/system/cpu/possible": {Data: []byte("0-7\n")},
+		"sys/devices/system/cpu/present":  {Data: []byte("0-5\n")},
+		"sys/devices/system/cpu/online":   {Data: []byte("0-3\n")},
+		"sys/devices/system/cpu/offline":  {Data: []byte("4-7\n")},
+		"sys/devices/system/cpu/isolated": {Data: []byte("2-3\n")},
+	}
+	v := Info{CPUs: []CPU{{Proc: 0}, {Proc: 1}, {Proc: 2}, {Proc: 3}}}
+	v.Masks = readCPUMasks(fsys)
+	v.Masks.Allowed = NewCPUSet(0, 1, 2)
+	applyCPUMasks(&v)
+
+	for _, tc := range []struct {
+		name string
+		got  CPUSet
+		want string
+	}{
+		{"possible", v.Masks.Possible, "0-7"},
+		{"present", v.Masks.Present, "0-5"},
+		{"online", v.Masks.Online, "0-3"},
+		{"offline", v.Masks.Offline, "4-7"},
+		{"isolated", v.Masks.Isolated, "2-3"},
+		{"nohz_full", v.Masks.NoHZFull, ""},
+	} {
+		if got := tc.got.String(); got != tc.want {
+			t.Fatalf("%s: expected %q, got %q", tc.name, tc.want, got)
+		}
+	}
+
+	wantCPUs := []CPU{
+		{Proc: 0, Online: true, Allowed: true},
+		{Proc: 1, Online: true, Allowed: true},
+		{Proc: 2, Online: true, Isolated: true, Allowed: true},
+		{Proc: 3, Online: true, Isolated: true},
+		{Proc: 4},
+		{Proc: 5},
+	}
+	if !reflect.DeepEqual(v.CPUs, wantCPUs) {
+		t.Fatalf("expected %#v, got %#v", wantCPUs, v.CPUs)
+	}
+}
@@ -0,0 +1,271 @@
+package sysinfo
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Handler returns an http.Handler that serves the host
+// information in the Prometheus text exposition format.
+//
+// The host is detected once, when Handler is called. Each
+// request re-reads the CPU frequencies and temperatures.
+//
+// The metrics are
+//
+//	sysinfo_cpu_info{cpu,vendor,model_name,microarch,part}
+//	sysinfo_cpu_feature{feature}
+//	sysinfo_cpu_cache_bytes{cpu,level}
+//	sysinfo_cpus{state}
+//	sysinfo_cores
+//	sysinfo_sockets
+//	sysinfo_threads_per_core
+//	sysinfo_numa_nodes
+//	sysinfo_numa_node_memory_bytes{node}
+//	sysinfo_system_info{vendor,product,board_vendor,board_name,board_model,soc}
+//	sysinfo_cpu_frequency_hertz{cpu}
+//	sysinfo_temperature_celsius{sensor,driver,source}
+//
+// Metrics that the host does not report are omitted.
+func Handler() http.Handler {
+	return newMetricsHandler(Detect(), os.DirFS("/"))
+}
+
+// metricsContentType is the content type of the Prometheus
+// text exposition format.
+const metricsContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+type metricsHandler struct {
+	// fsys is the file system the frequencies are read from.
+	// It should be rooted at "/".
+	fsys fs.FS
+
+	mu   sync.Mutex
+	info Info
+}
+
+func newMetricsHandler(info Info, fsys fs.FS) *metricsHandler {
+	return &metricsHandler{
+		fsys: fsys,
+		info: info,
+	}
+}
+
+func (h *metricsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	h.mu.Lock()
+	// Sensors that cannot be read keep their previous
+	// temperature.
+	h.info.Thermal.Refresh()
+	freq := readFreqs(h.fsys, h.info.CPUs)
+	var buf bytes.Buffer
+	writeMetrics(&buf, h.info, freq)
+	h.mu.Unlock()
+
+	w.Header().Set("Content-Type", metricsContentType)
+	w.Header().Set("Content-Length", strconv.Itoa(buf.Len()))
+	if r.Method != http.MethodHead {
+		w.Write(buf.Bytes())
+	}
+}
+
+// readFreqs returns the current frequency of each CPU in MHz.
+//
+// CPUs without cpufreq support keep the frequency reported by
+// /proc/cpuinfo.
+func readFreqs(fsys fs.FS, cpus []CPU) []float64 {
+	freq := make([]float64, len(cpus))
+	for i, c := range cpus {
+		freq[i] = c.Freq
+		if khz, err := strconv.ParseUint(readString(fsys, freqFile(c.Proc)), 10, 64); err == nil {
+			freq[i] = float64(khz) / 1e3
+		}
+	}
+	return freq
+}
+
+// writeMetrics writes v in the Prometheus text exposition
+// format, where freq is the frequency of each CPU in MHz.
+func writeMetrics(w io.Writer, v Info, freq []float64) error {
+	m := metricWriter{w: bufio.NewWriter(w)}
+
+	m.family("sysinfo_cpu_info", "CPU identity. Always 1.")
+	for _, c := range v.onlineCPUs() {
+		vendor := c.VendorID
+		if vendor == "" && c.Impl != 0 {
+			vendor = c.Impl.String()
+		}
+		part := ""
+		if c.Part != 0 {
+			part = c.Part.String()
+		}
+		m.sample(1,
+			"cpu", strconv.Itoa(c.Proc),
+			"vendor", vendor,
+			"model_name", c.ModelName,
+			"microarch", c.MicroArch,
+			"part", part)
+	}
+
+	m.family("sysinfo_cpu_feature", "Number of CPUs that support the feature.")
+	counts := make(map[string]int)
+	for _, c := range v.CPUs {
+		for _, f := range c.Features {
+			counts[f]++
+		}
+	}
+	feats := make([]string, 0, len(counts))
+	for f := range counts {
+		feats = append(feats, f)
+	}
+	sort.Strings(feats)
+	for _, f := range feats {
+		m.sample(float64(counts[f]), "feature", f)
+	}
+
+	m.family("sysinfo_cpu_cache_bytes", "Size of the CPU's caches in bytes.")
+	for _, c := range v.CPUs {
+		cpu := strconv.Itoa(c.Proc)
+		for _, l := range []struct {
+			level string
+			size  int
+		}{
+			{"l1d", c.Cache.L1},
+			{"l1i", c.Cache.Inst},
+			{"l2", c.Cache.L2},
+			{"l3", c.Cache.L3},
+		} {
+			if l.size != 0 {
+				m.sample(float64(l.size), "cpu", cpu, "level", l.level)
+			}
+		}
+	}
+
+	m.family("sysinfo_cpus", "Number of logical CPUs in each state.")
+	if len(v.CPUs) > 0 {
+		present, online := v.lscpuMasks()
+		m.sample(float64(present.Len()), "state", "present")
+		m.sample(float64(online.Len()), "state", "online")
+		m.sample(float64(present.Difference(online).Len()), "state", "offline")
+		m.sample(float64(v.Masks.Isolated.Len()), "state", "isolated")
+	}
+
+	if t := v.lscpuTopology(); t.sockets > 0 {
+		m.family("sysinfo_cores", "Number of physical CPU cores.")
+		m.sample(float64(t.cores * t.sockets))
+		m.family("sysinfo_sockets", "Number of CPU sockets.")
+		m.sample(float64(t.sockets))
+		m.family("sysinfo_threads_per_core", "Number of hardware threads per core.")
+		m.sample(float64(t.threads))
+	}
+
+	if len(v.NUMA) > 0 {
+		m.family("sysinfo_numa_nodes", "Number of NUMA nodes.")
+		m.sample(float64(len(v.NUMA)))
+	}
+	m.family("sysinfo_numa_node_memory_bytes", "Total memory of the NUMA node in bytes.")
+	for _, n := range v.NUMA {
+		if n.MemTotal != 0 {
+			m.sample(float64(n.MemTotal), "node", strconv.Itoa(n.ID))
+		}
+	}
+
+	m.family("sysinfo_system_info", "System identity. Always 1.")
+	sys := []string{
+		"vendor", v.System.Vendor,
+		"product", v.System.Product,
+		"board_vendor", v.System.Board.Vendor,
+		"board_name", v.System.Board.Name,
+		"board_model", v.Board.Model,
+		"soc", v.Board.SoC,
+	}
+	for i := 1; i < len(sys); i += 2 {
+		if sys[i] != "" {
+			m.sample(1, sys...)
+			break
+		}
+	}
+
+	m.family("sysinfo_cpu_frequency_hertz", "Current CPU frequency in hertz.")
+	for i, c := range v.CPUs {
+		if i < len(freq) && freq[i] != 0 {
+			m.sample(freq[i]*1e6, "cpu", strconv.Itoa(c.Proc))
+		}
+	}
+
+	m.family("sysinfo_temperature_celsius", "Current temperature of the sensor in degrees Celsius.")
+	for _, s := range v.Thermal.Sensors {
+		m.sample(s.Temp, "sensor", s.Name, "driver", s.Driver, "source", s.Source)
+	}
+
+	return m.flush()
+}
+
+// metricWriter writes metrics in the Prometheus text
+// exposition format.
+//
+// Each family's HELP and TYPE lines are only written once it
+// has a sample.
+type metricWriter struct {
+	w    *bufio.Writer
+	name string
+	help string
+	// started is whether the family's header has been
+	// written.
+	started bool
+}
+
+// family starts a gauge family.
+func (m *metricWriter) family(name, help string) {
+	m.name = name
+	m.help = help
+	m.started = false
+}
+
+// sample writes a sample of the current family with the
+// provided label names and values.
+func (m *metricWriter) sample(v float64, labels ...string) {
+	if !m.started {
+		m.w.WriteString("# HELP " + m.name + " " + m.help + "\n")
+		m.w.WriteString("# TYPE " + m.name + " gauge\n")
+		m.started = true
+	}
+	m.w.WriteString(m.name)
+	for i := 0; i+1 < len(labels); i += 2 {
+		if i == 0 {
+			m.w.WriteByte('{')
+		} else {
+			m.w.WriteByte(',')
+		}
+		m.w.WriteString(labels[i])
+		m.w.WriteString(`="`)
+		labelEscaper.WriteString(m.w, labels[i+1])
+		m.w.WriteByte('"')
+	}
+	if len(labels) > 0 {
+		m.w.WriteByte('}')
+	}
+	m.w.WriteByte(' ')
+	m.w.WriteString(strconv.FormatFloat(v, 'g', -1, 64))
+	m.w.WriteByte('\n')
+}
+
+func (m *metricWriter) flush() error {
+	return m.w.Flush()
+}
+
+// labelEscaper escapes label values.
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
@@ -0,0 +1,147 @@
+package sysinfo
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestHandler(t *testing.T) {
+	file := func(s string) *fstest.MapFile {
+		return &fstest.MapFile{Data: []byte(s + "\n")}
+	}
+	fsys := fstest.MapFS{
+		"sys/class/thermal/thermal_zone0/type":                 file("cpu-thermal"),
+		"sys/class/thermal/thermal_zone0/temp":                 file("45000"),
+		"sys/devices/system/cpu/cpu0/cpufreq/scaling_cur_freq": file("1800000"),
+	}
+	info := Info{
+		CPUs: []CPU{
+			{Proc: 0, Online: true, Impl: ARMLtd, Part: CortexA72, MicroArch: "Cortex-A72",
+				Features: []string{"fp", "asimd", "crc32"}, Cache: Cache{L1: 32 << 10, Inst: 48 << 10}},
+			{Proc: 1, Online: true, Impl: ARMLtd, Part: CortexA53,
+				Features: []string{"fp", "asimd"}, Freq: 1400},
+		},
+		NUMA: []NUMANode{
+			{ID: 0, CPUs: NewCPUSet(0, 1), MemTotal: 4 << 30},
+		},
+		Board:   Board{Model: `Raspberry Pi "4"`, SoC: "BCM2711"},
+		Thermal: readThermal(fsys),
+	}
+	srv := httptest.NewServer(newMetricsHandler(info, fsys))
+	defer srv.Close()
+
+	scrape := func() string {
+		t.Helper()
+		resp, err := http.Get(srv.URL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("unexpected status: %s", resp.Status)
+		}
+		if ct := resp.Header.Get("Content-Type"); ct != metricsContentType {
+			t.Fatalf("unexpected content type: %q", ct)
+		}
+		buf, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(buf)
+	}
+
+	const want = `# HELP sysinfo_cpu_info CPU identity. Always 1.
+# TYPE sysinfo_cpu_info gauge
+sysinfo_cpu_info{cpu="0",vendor="ARM Ltd",model_name="",microarch="Cortex-A72",part="Cortex-A72 (0xd08)"} 1
+sysinfo_cpu_info{cpu="1",vendor="ARM Ltd",model_name="",microarch="",part="Cortex-A53 (0xd03)"} 1
+# HELP sysinfo_cpu_feature Number of CPUs that support the feature.
+# TYPE sysinfo_cpu_feature gauge
+sysinfo_cpu_feature{feature="asimd"} 2
+sysinfo_cpu_feature{feature="crc32"} 1
+sysinfo_cpu_feature{feature="fp"} 2
+# HELP sysinfo_cpu_cache_bytes Size of the CPU's caches in bytes.
+# TYPE sysinfo_cpu_cache_bytes gauge
+sysinfo_cpu_cache_bytes{cpu="0",level="l1d"} 32768
+sysinfo_cpu_cache_bytes{cpu="0",level="l1i"} 49152
+# HELP sysinfo_cpus Number of logical CPUs in each state.
+# TYPE sysinfo_cpus gauge
+sysinfo_cpus{state="present"} 2
+sysinfo_cpus{state="online"} 2
+sysinfo_cpus{state="offline"} 0
+sysinfo_cpus{state="isolated"} 0
+# HELP sysinfo_cores Number of physical CPU cores.
+# TYPE sysinfo_cores gauge
+sysinfo_cores 2
+# HELP sysinfo_sockets Number of CPU sockets.
+# TYPE sysinfo_sockets gauge
+sysinfo_sockets 1
+# HELP sysinfo_threads_per_core Number of hardware threads per core.
+# TYPE sysinfo_threads_per_core gauge
+sysinfo_threads_per_core 1
+# HELP sysinfo_numa_nodes Number of NUMA nodes.
+# TYPE sysinfo_numa_nodes gauge
+sysinfo_numa_nodes 1
+# HELP sysinfo_numa_node_memory_bytes Total memory of the NUMA node in bytes.
+# TYPE sysinfo_numa_node_memory_bytes gauge
+sysinfo_numa_node_memory_bytes{node="0"} 4.294967296e+09
+# HELP sysinfo_system_info System identity. Always 1.
+# TYPE sysinfo_system_info gauge
+sysinfo_system_info{vendor="",product="",board_vendor="",board_name="",board_model="Raspberry Pi \"4\"",soc="BCM2711"} 1
+# HELP sysinfo_cpu_frequency_hertz Current CPU frequency in hertz.
+# TYPE sysinfo_cpu_frequency_hertz gauge
+sysinfo_cpu_frequency_hertz{cpu="0"} 1.8e+09
+sysinfo_cpu_frequency_hertz{cpu="1"} 1.4e+09
+# HELP sysinfo_temperature_celsius Current temperature of the sensor in degrees Celsius.
+# TYPE sysinfo_temperature_celsius gauge
+sysinfo_temperature_celsius{sensor="cpu-thermal",driver="cpu-thermal",source="thermal_zone0"} 45
+`
+	if got := scrape(); got != want {
+		t.Fatalf("expected\n%s\ngot\n%s", want, got)
+	}
+
+	fsys["sys/class/thermal/thermal_zone0/temp"] = file("51500")
+	fsys["sys/devices/system/cpu/cpu0/cpufreq/scaling_cur_freq"] = file("600000")
+	got := scrape()
+	for _, line := range []string{
+		`sysinfo_cpu_frequency_hertz{cpu="0"} 6e+08` + "\n",
+		`sysinfo_temperature_celsius{sensor="cpu-thermal",driver="cpu-thermal",source="thermal_zone0"} 51.5` + "\n",
+	} {
+		if !strings.Contains(got, line) {
+			t.Fatalf("expected %q in\n%s", line, got)
+		}
+	}
+
+	resp, err := http.Post(srv.URL, "text/plain", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusMethodNotAllowed {
+		t.Fatalf("expected %d, got %s", http.StatusMethodNotAllowed, resp.Status)
+	}
+}
+
+func TestMetricsOffline(t *testing.T) {
+	var v Info
+	v.CPUs = []CPU{{Proc: 0, VendorID: "GenuineIntel", Features: []string{"fpu"}}}
+	v.Masks.Present = NewCPUSet(0, 1)
+	v.Masks.Online = NewCPUSet(0)
+	applyCPUMasks(&v)
+
+	var buf bytes.Buffer
+	if err := writeMetrics(&buf, v, nil); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	if strings.Contains(out, `sysinfo_cpu_info{cpu="1"`) {
+		t.Fatalf("unexpected info for offline CPU:\n%s", out)
+	}
+	if !strings.Contains(out, `sysinfo_cpus{state="offline"} 1`) {
+		t.Fatalf("expected an offline CPU:\n%s", out)
+	}
+}
@@ -0,0 +1,109 @@
+package sysinfo
+
+import (
+	"bufio"
+	"bytes"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// NUMANode describes a NUMA memory node.
+//
+// On Linux, this information is read from
+// /sys/devices/system/node/nodeN.
+type NUMANode struct {
+	// ID is the node's number.
+	ID int `json:"id"`
+	// CPUs is the set of CPUs local to the node.
+	//
+	// Matches: cpulist
+	CPUs CPUSet `json:"cpus"`
+	// MemTotal is the node's total memory in bytes.
+	//
+	// Matches: MemTotal
+	MemTotal uint64 `json:"mem_total"`
+	// MemFree is the node's free memory in bytes at the time
+	// it was detected.
+	//
+	// Matches: MemFree
+	MemFree uint64 `json:"mem_free"`
+	// Distance is the relative distance from the node to
+	// each node, indexed by node ID. The distance to the
+	// node itself is usually 10.
+	//
+	// Matches: distance
+	Distance []int `json:"distance,omitempty"`
+}
+
+const nodeDir = "sys/devices/system/node"
+
+// readNUMA reads the NUMA nodes in /sys/devices/system/node.
+//
+// fsys should be rooted at "/". It returns nil if the host
+// does not have NUMA support.
+func readNUMA(fsys fs.FS) []NUMANode {
+	ents, err := fs.ReadDir(fsys, nodeDir)
+	if err != nil {
+		return nil
+	}
+	var nodes []NUMANode
+	for _, e := range ents {
+		id, ok := nodeID(e.Name())
+		if !ok {
+			continue
+		}
+		dir := path.Join(nodeDir, e.Name())
+		n := NUMANode{ID: id}
+		n.CPUs, _ = ParseCPUList(readString(fsys, path.Join(dir, "cpulist")))
+		if buf, err := fs.ReadFile(fsys, path.Join(dir, "meminfo")); err == nil {
+			parseNodeMeminfo(&n, buf)
+		}
+		for _, f := range strings.Fields(readString(fsys, path.Join(dir, "distance"))) {
+			n.Distance = append(n.Distance, atoi(f))
+		}
+		nodes = append(nodes, n)
+	}
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodes[i].ID < nodes[j].ID
+	})
+	return nodes
+}
+
+// nodeID parses a "nodeN" directory name.
+func nodeID(name string) (int, bool) {
+	s := strings.TrimPrefix(name, "node")
+	if s == name {
+		return 0, false
+	}
+	id, err := strconv.Atoi(s)
+	return id, err == nil && id >= 0
+}
+
+// parseNodeMeminfo parses a node's meminfo file.
+//
+// It should look like
+//
+//	Node 0 MemTotal:       16318208 kB
+//	Node 0 MemFree:         9127372 kB
+func parseNodeMeminfo(n *NUMANode, buf []byte) {
+	sc := bufio.NewScanner(bytes.NewReader(buf))
+	for sc.Scan() {
+		f := strings.Fields(sc.Text())
+		if len(f) != 5 || f[0] != "Node" || f[4] != "kB" {
+			continue
+		}
+		v, err := strconv.ParseUint(f[3], 10, 64)
+		if err != nil {
+			continue
+		}
+		switch f[2] {
+		case "MemTotal:":
+			n.MemTotal = v << 10
+		case "MemFree:":
+			n.MemFree = v << 10
+		}
+	}
+}
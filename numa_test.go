@@ -0,0 +1,48 @@
+package sysinfo
+
+import (
+	"reflect"
+	"testing"
+	"testing/fstest"
+)
+
+func TestReadNUMA(t *testing.T) {
+	file := func(s string) *fstest.MapFile {
+		return &fstest.MapFile{Data: []byte(s + "\n")}
+	}
+	const dir = "sys/devices/system/node/"
+	fsys := fstest.MapFS{
+		dir + "online":        file("0-1"),
+		dir + "node0/cpulist": file("0-3,8-11"),
+		dir + "node0/meminfo": file("Node 0 MemTotal:       16318208 kB\n" +
+			"Node 0 MemFree:         9127372 kB\n" +
+			"Node 0 MemUsed:         7190836 kB"),
+		dir + "node0/distance": file("10 21"),
+		dir + "node1/cpulist":  file("4-7,12-15"),
+		dir + "node1/distance": file("21 10"),
+		dir + "node10/cpulist": file(""),
+	}
+	got := readNUMA(fsys)
+	want := []NUMANode{
+		{
+			ID:       0,
+			CPUs:     NewCPUSet(0, 1, 2, 3, 8, 9, 10, 11),
+			MemTotal: 16318208 << 10,
+			MemFree:  9127372 << 10,
+			Distance: []int{10, 21},
+		},
+		{
+			ID:       1,
+			CPUs:     NewCPUSet(4, 5, 6, 7, 12, 13, 14, 15),
+			Distance: []int{21, 10},
+		},
+		{ID: 10},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %+v, got %+v", want, got)
+	}
+
+	if nodes := readNUMA(fstest.MapFS{}); nodes != nil {
+		t.Fatalf("expected no nodes, got %+v", nodes)
+	}
+}
@@ -0,0 +1,228 @@
+package sysinfo
+
+import (
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Power describes the host's RAPL (Running Average Power
+// Limit) energy counters.
+//
+// On Linux, this information is read from
+// /sys/class/powercap. AMD processors expose their RAPL
+// counters through the same intel-rapl interface.
+type Power struct {
+	// Domains is every RAPL domain, parents before their
+	// subdomains.
+	Domains []PowerDomain `json:"domains,omitempty"`
+
+	// fsys is the file system the domains were read from.
+	fsys fs.FS
+}
+
+// PowerDomain is a single RAPL power domain.
+type PowerDomain struct {
+	// Name is the domain name, for example "package-0",
+	// "core", "uncore", "dram", or "psys".
+	//
+	// Matches: name
+	Name string `json:"name"`
+	// Zone is the powercap zone, for example
+	// "intel-rapl:0:1".
+	Zone string `json:"zone"`
+	// Parent is the parent's powercap zone, or empty if the
+	// domain is a top-level domain.
+	Parent string `json:"parent,omitempty"`
+	// Enabled is whether power limiting is enabled.
+	//
+	// Matches: enabled
+	Enabled bool `json:"enabled"`
+	// Energy is the energy counter in microjoules at the time
+	// the domain was read.
+	//
+	// Matches: energy_uj
+	Energy uint64 `json:"energy_uj"`
+	// MaxEnergyRange is the value in microjoules at which the
+	// energy counter wraps around to zero.
+	//
+	// Matches: max_energy_range_uj
+	MaxEnergyRange uint64 `json:"max_energy_range_uj"`
+	// Constraints are the domain's power limits.
+	Constraints []PowerConstraint `json:"constraints,omitempty"`
+}
+
+// PowerConstraint is a RAPL power limit.
+type PowerConstraint struct {
+	// Name is the constraint name, for example "long_term",
+	// "short_term", or "peak_power".
+	//
+	// Matches: constraint_N_name
+	Name string `json:"name"`
+	// PowerLimit is the power limit in microwatts.
+	//
+	// Matches: constraint_N_power_limit_uw
+	PowerLimit uint64 `json:"power_limit_uw"`
+	// TimeWindow is the time window over which the power
+	// limit is averaged in microseconds.
+	//
+	// Matches: constraint_N_time_window_us
+	TimeWindow uint64 `json:"time_window_us,omitempty"`
+	// MaxPower is the maximum allowed power limit in
+	// microwatts, or zero if unknown.
+	//
+	// Matches: constraint_N_max_power_uw
+	MaxPower uint64 `json:"max_power_uw,omitempty"`
+}
+
+// EnergySample is a reading of a domain's energy counter.
+type EnergySample struct {
+	// Time is when the counter was read.
+	Time time.Time `json:"time"`
+	// Energy is the counter in microjoules.
+	Energy uint64 `json:"energy_uj"`
+}
+
+// ReadPower reads the host's RAPL power domains.
+//
+// Reading the energy counters usually requires root.
+func ReadPower() Power {
+	return readHostPower()
+}
+
+// Sample reads the current energy counter of each domain.
+//
+// The samples are in the same order as p.Domains.
+//
+// Sample returns an error if p was not read from the host,
+// for example if it was decoded from JSON.
+func (p Power) Sample() ([]EnergySample, error) {
+	if p.fsys == nil && len(p.Domains) > 0 {
+		return nil, errNotFromHost
+	}
+	s := make([]EnergySample, len(p.Domains))
+	for i, d := range p.Domains {
+		now := time.Now()
+		v, err := readUint(p.fsys, path.Join(powercapDir, d.Zone, "energy_uj"))
+		if err != nil {
+			return nil, err
+		}
+		s[i] = EnergySample{Time: now, Energy: v}
+	}
+	return s, nil
+}
+
+// Watts returns the average power in watts of each domain
+// between two sets of samples returned by Sample.
+func (p Power) Watts(a, b []EnergySample) []float64 {
+	w := make([]float64, len(p.Domains))
+	for i, d := range p.Domains {
+		if i < len(a) && i < len(b) {
+			w[i] = d.Watts(a[i], b[i])
+		}
+	}
+	return w
+}
+
+// Watts returns the domain's average power in watts between
+// two samples.
+//
+// If the counter wrapped around between a and b, Watts uses
+// MaxEnergyRange to account for it. The counter must not wrap
+// around more than once.
+func (d PowerDomain) Watts(a, b EnergySample) float64 {
+	dt := b.Time.Sub(a.Time).Seconds()
+	if dt <= 0 {
+		return 0
+	}
+	var uj uint64
+	if b.Energy >= a.Energy {
+		uj = b.Energy - a.Energy
+	} else {
+		uj = d.MaxEnergyRange - a.Energy + b.Energy
+	}
+	return float64(uj) / 1e6 / dt
+}
+
+const powercapDir = "sys/class/powercap"
+
+// readPower reads /sys/class/powercap.
+//
+// fsys should be rooted at "/".
+func readPower(fsys fs.FS) Power {
+	p := Power{fsys: fsys}
+	ents, err := fs.ReadDir(fsys, powercapDir)
+	if err != nil {
+		return p
+	}
+	var zones []string
+	for _, e := range ents {
+		name := e.Name()
+		if strings.HasPrefix(name, "intel-rapl:") ||
+			strings.HasPrefix(name, "intel-rapl-mmio:") {
+			zones = append(zones, name)
+		}
+	}
+	sort.Slice(zones, func(i, j int) bool {
+		return zoneLess(zones[i], zones[j])
+	})
+	for _, z := range zones {
+		dir := path.Join(powercapDir, z)
+		d := PowerDomain{
+			Name:    readString(fsys, dir+"/name"),
+			Zone:    z,
+			Enabled: readString(fsys, dir+"/enabled") == "1",
+		}
+		if i := strings.LastIndexByte(z, ':'); strings.Count(z, ":") > 1 {
+			d.Parent = z[:i]
+		}
+		d.Energy, _ = readUint(fsys, dir+"/energy_uj")
+		d.MaxEnergyRange, _ = readUint(fsys, dir+"/max_energy_range_uj")
+		for i := 0; ; i++ {
+			prefix := fmt.Sprintf("%s/constraint_%d_", dir, i)
+			limit, err := readUint(fsys, prefix+"power_limit_uw")
+			if err != nil {
+				break
+			}
+			c := PowerConstraint{
+				Name:       readString(fsys, prefix+"name"),
+				PowerLimit: limit,
+			}
+			c.TimeWindow, _ = readUint(fsys, prefix+"time_window_us")
+			c.MaxPower, _ = readUint(fsys, prefix+"max_power_uw")
+			d.Constraints = append(d.Constraints, c)
+		}
+		p.Domains = append(p.Domains, d)
+	}
+	return p
+}
+
+// zoneLess orders powercap zones like "intel-rapl:0:1"
+// numerically, with parents before their subzones.
+func zoneLess(a, b string) bool {
+	as, bs := strings.Split(a, ":"), strings.Split(b, ":")
+	if as[0] != bs[0] {
+		return as[0] < bs[0]
+	}
+	for i := 1; i < len(as) && i < len(bs); i++ {
+		x, _ := strconv.Atoi(as[i])
+		y, _ := strconv.Atoi(bs[i])
+		if x != y {
+			return x < y
+		}
+	}
+	return len(as) < len(bs)
+}
+
+// readUint reads a file containing an unsigned integer.
+func readUint(fsys fs.FS, name string) (uint64, error) {
+	buf, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseUint(strings.TrimSpace(string(buf)), 10, 64)
+}
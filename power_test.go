@@ -0,0 +1,103 @@
+package sysinfo
+
+import (
+	"math"
+	"reflect"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+func TestReadPower(t *testing.T) {
+	file := func(s string) *fstest.MapFile {
+		return &fstest.MapFile{Data: []byte(s + "\n")}
+	}
+	const dir = "sys/class/powercap/"
+	fsys := fstest.MapFS{
+		dir + "intel-rapl/enabled": file("1"),
+
+		dir + "intel-rapl:0/name":                          file("package-0"),
+		dir + "intel-rapl:0/enabled":                       file("1"),
+		dir + "intel-rapl:0/energy_uj":                     file("52069498017"),
+		dir + "intel-rapl:0/max_energy_range_uj":           file("262143328850"),
+		dir + "intel-rapl:0/constraint_0_name":             file("long_term"),
+		dir + "intel-rapl:0/constraint_0_power_limit_uw":   file("150000000"),
+		dir + "intel-rapl:0/constraint_0_time_window_us":   file("999424"),
+		dir + "intel-rapl:0/constraint_0_max_power_uw":     file("150000000"),
+		dir + "intel-rapl:0/constraint_1_name":             file("short_term"),
+		dir + "intel-rapl:0/constraint_1_power_limit_uw":   file("180000000"),
+		dir + "intel-rapl:0/constraint_1_time_window_us":   file("2440"),
+		dir + "intel-rapl:0:0/name":                        file("dram"),
+		dir + "intel-rapl:0:0/enabled":                     file("0"),
+		dir + "intel-rapl:0:0/energy_uj":                   file("9405412351"),
+		dir + "intel-rapl:0:0/max_energy_range_uj":         file("65712999613"),
+		dir + "intel-rapl:0:0/constraint_0_name":           file("long_term"),
+		dir + "intel-rapl:0:0/constraint_0_power_limit_uw": file("0"),
+		dir + "intel-rapl:10/name":                         file("package-10"),
+		dir + "intel-rapl:10/energy_uj":                    file("1"),
+		dir + "intel-rapl:1/name":                          file("package-1"),
+		dir + "intel-rapl:1/energy_uj":                     file("2"),
+	}
+	p := readPower(fsys)
+
+	want := []PowerDomain{
+		{
+			Name:           "package-0",
+			Zone:           "intel-rapl:0",
+			Enabled:        true,
+			Energy:         52069498017,
+			MaxEnergyRange: 262143328850,
+			Constraints: []PowerConstraint{
+				{Name: "long_term", PowerLimit: 150000000, TimeWindow: 999424, MaxPower: 150000000},
+				{Name: "short_term", PowerLimit: 180000000, TimeWindow: 2440},
+			},
+		},
+		{
+			Name:           "dram",
+			Zone:           "intel-rapl:0:0",
+			Parent:         "intel-rapl:0",
+			Energy:         9405412351,
+			MaxEnergyRange: 65712999613,
+			Constraints:    []PowerConstraint{{Name: "long_term"}},
+		},
+		{Name: "package-1", Zone: "intel-rapl:1", Energy: 2},
+		{Name: "package-10", Zone: "intel-rapl:10", Energy: 1},
+	}
+	if !reflect.DeepEqual(p.Domains, want) {
+		t.Fatalf("expected %#v, got %#v", want, p.Domains)
+	}
+
+	a, err := p.Sample()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a[0].Energy != 52069498017 {
+		t.Fatalf("unexpected sample: %#v", a[0])
+	}
+
+	decoded := Power{Domains: p.Domains}
+	if _, err := decoded.Sample(); err == nil {
+		t.Fatal("expected an error")
+	}
+}
+
+func TestPowerDomainWatts(t *testing.T) {
+	d := PowerDomain{MaxEnergyRange: 262143328850}
+	t0 := time.Unix(1000, 0)
+	t1 := t0.Add(2 * time.Second)
+
+	for _, tc := range []struct {
+		a, b EnergySample
+		want float64
+	}{
+		{EnergySample{t0, 1_000_000}, EnergySample{t1, 101_000_000}, 50},
+		// Wraparound.
+		{EnergySample{t0, 262143328850 - 30_000_000}, EnergySample{t1, 50_000_000}, 40},
+		{EnergySample{t1, 0}, EnergySample{t0, 1}, 0},
+	} {
+		got := d.Watts(tc.a, tc.b)
+		if math.Abs(got-tc.want) > 1e-9 {
+			t.Fatalf("expected %v, got %v", tc.want, got)
+		}
+	}
+}
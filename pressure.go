@@ -0,0 +1,213 @@
+package sysinfo
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io/fs"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// Pressure is pressure stall information (PSI): the share of
+// time tasks were stalled waiting for a resource.
+//
+// On Linux, host-wide pressure is read from /proc/pressure and
+// cgroup pressure is read from the cgroup v2 *.pressure files.
+// Each resource is nil if the kernel does not report it.
+type Pressure struct {
+	// Cgroup is the cgroup v2 path the pressure was read
+	// from, for example "/system.slice/foo.service", or empty
+	// for host-wide pressure.
+	Cgroup string `json:"cgroup,omitempty"`
+	// CPU is CPU pressure.
+	//
+	// Matches: cpu, cpu.pressure
+	CPU *PressureStat `json:"cpu,omitempty"`
+	// Memory is memory pressure.
+	//
+	// Matches: memory, memory.pressure
+	Memory *PressureStat `json:"memory,omitempty"`
+	// IO is I/O pressure.
+	//
+	// Matches: io, io.pressure
+	IO *PressureStat `json:"io,omitempty"`
+	// IRQ is interrupt pressure, which only has a Full line.
+	//
+	// Matches: irq, irq.pressure
+	IRQ *PressureStat `json:"irq,omitempty"`
+}
+
+// PressureStat is the pressure of a single resource.
+type PressureStat struct {
+	// Some is the time at least one task was stalled.
+	Some *PressureAvg `json:"some,omitempty"`
+	// Full is the time every non-idle task was stalled at
+	// the same time.
+	//
+	// Host-wide CPU pressure always reports zero for Full.
+	Full *PressureAvg `json:"full,omitempty"`
+}
+
+// PressureAvg is a pressure line.
+type PressureAvg struct {
+	// Avg10 is the percentage of time stalled over the last
+	// 10 seconds.
+	Avg10 float64 `json:"avg10"`
+	// Avg60 is the percentage of time stalled over the last
+	// 60 seconds.
+	Avg60 float64 `json:"avg60"`
+	// Avg300 is the percentage of time stalled over the last
+	// 300 seconds.
+	Avg300 float64 `json:"avg300"`
+	// Total is the total time stalled in microseconds.
+	Total uint64 `json:"total_us"`
+}
+
+// ReadPressure reads the host-wide pressure.
+func ReadPressure() Pressure {
+	return readHostPressure()
+}
+
+// ReadCgroupPressure reads the pressure of the cgroup v2
+// directory dir, for example
+// "/sys/fs/cgroup/system.slice".
+//
+// It returns an error if dir has no pressure files.
+func ReadCgroupPressure(dir string) (Pressure, error) {
+	p, ok := readPressureFiles(os.DirFS(dir), ".", ".pressure")
+	if !ok {
+		return Pressure{}, errNoPressure
+	}
+	return p, nil
+}
+
+var errNoPressure = errors.New("sysinfo: no pressure information")
+
+// readPressure reads /proc/pressure.
+//
+// fsys should be rooted at "/".
+func readPressure(fsys fs.FS) Pressure {
+	p, _ := readPressureFiles(fsys, "proc/pressure", "")
+	return p
+}
+
+// readCgroupPressure reads the pressure of the current
+// process's cgroup, if it is in a cgroup v2 hierarchy.
+//
+// fsys should be rooted at "/".
+func readCgroupPressure(fsys fs.FS) *Pressure {
+	cg := cgroupPath(readString(fsys, "proc/self/cgroup"))
+	if cg == "" {
+		return nil
+	}
+	p, ok := readPressureFiles(fsys, path.Join("sys/fs/cgroup", cg), ".pressure")
+	if !ok {
+		return nil
+	}
+	p.Cgroup = cg
+	return &p
+}
+
+// cgroupPath returns the cgroup v2 path from /proc/self/cgroup.
+//
+// It should look like
+//
+//	0::/user.slice/user-1000.slice/session-2.scope
+//
+// Hosts that also mount cgroup v1 controllers have additional
+// lines, which are ignored.
+func cgroupPath(s string) string {
+	for _, line := range strings.Split(s, "\n") {
+		if p := strings.TrimPrefix(line, "0::"); p != line {
+			return p
+		}
+	}
+	return ""
+}
+
+// readPressureFiles reads the cpu, memory, io, and irq
+// pressure files in dir with the provided suffix.
+//
+// It reports whether any of the files could be read.
+func readPressureFiles(fsys fs.FS, dir, suffix string) (Pressure, bool) {
+	var p Pressure
+	ok := false
+	for _, r := range []struct {
+		name string
+		dst  **PressureStat
+	}{
+		{"cpu", &p.CPU},
+		{"memory", &p.Memory},
+		{"io", &p.IO},
+		{"irq", &p.IRQ},
+	} {
+		buf, err := fs.ReadFile(fsys, path.Join(dir, r.name+suffix))
+		if err != nil {
+			continue
+		}
+		s, err := parsePressure(buf)
+		if err != nil {
+			continue
+		}
+		*r.dst = &s
+		ok = true
+	}
+	return p, ok
+}
+
+// parsePressure parses a pressure file.
+//
+// It should look like
+//
+//	some avg10=0.00 avg60=0.00 avg300=0.00 total=0
+//	full avg10=0.00 avg60=0.00 avg300=0.00 total=0
+func parsePressure(buf []byte) (PressureStat, error) {
+	var s PressureStat
+	sc := bufio.NewScanner(bytes.NewReader(buf))
+	for sc.Scan() {
+		f := strings.Fields(sc.Text())
+		if len(f) == 0 {
+			continue
+		}
+		var a PressureAvg
+		for _, kv := range f[1:] {
+			i := strings.IndexByte(kv, '=')
+			if i < 0 {
+				return PressureStat{}, errInvalidPressure
+			}
+			k, v := kv[:i], kv[i+1:]
+			var err error
+			switch k {
+			case "avg10":
+				a.Avg10, err = strconv.ParseFloat(v, 64)
+			case "avg60":
+				a.Avg60, err = strconv.ParseFloat(v, 64)
+			case "avg300":
+				a.Avg300, err = strconv.ParseFloat(v, 64)
+			case "total":
+				a.Total, err = strconv.ParseUint(v, 10, 64)
+			}
+			if err != nil {
+				return PressureStat{}, errInvalidPressure
+			}
+		}
+		switch f[0] {
+		case "some":
+			s.Some = &a
+		case "full":
+			s.Full = &a
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return PressureStat{}, err
+	}
+	if s.Some == nil && s.Full == nil {
+		return PressureStat{}, errInvalidPressure
+	}
+	return s, nil
+}
+
+var errInvalidPressure = errors.New("sysinfo: invalid pressure file")
@@ -0,0 +1,85 @@
+package sysinfo
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"testing/fstest"
+)
+
+func TestReadPressure(t *testing.T) {
+	fsys := fstest.MapFS{
+		"proc/pressure/cpu":    {Data: []byte("some avg10=1.53 avg60=0.87 avg300=0.42 total=163578\nfull avg10=0.00 avg60=0.00 avg300=0.00 total=0\n")},
+		"proc/pressure/memory": {Data: []byte("some avg10=0.00 avg60=0.10 avg300=0.05 total=2048\nfull avg10=0.00 avg60=0.02 avg300=0.01 total=1024\n")},
+		"proc/pressure/io":     {Data: []byte("some avg10=12.50 avg60=8.00 avg300=3.25 total=99999999\nfull avg10=10.00 avg60=6.00 avg300=2.00 total=88888888\n")},
+		"proc/self/cgroup":     {Data: []byte("12:cpuset:/\n0::/system.slice/agent.service\n")},
+		"sys/fs/cgroup/system.slice/agent.service/cpu.pressure": {Data: []byte("some avg10=25.00 avg60=20.00 avg300=15.00 total=5000000\nfull avg10=5.00 avg60=4.00 avg300=3.00 total=1000000\n")},
+	}
+	got := readPressure(fsys)
+	want := Pressure{
+		CPU: &PressureStat{
+			Some: &PressureAvg{Avg10: 1.53, Avg60: 0.87, Avg300: 0.42, Total: 163578},
+			Full: &PressureAvg{},
+		},
+		Memory: &PressureStat{
+			Some: &PressureAvg{Avg60: 0.10, Avg300: 0.05, Total: 2048},
+			Full: &PressureAvg{Avg60: 0.02, Avg300: 0.01, Total: 1024},
+		},
+		IO: &PressureStat{
+			Some: &PressureAvg{Avg10: 12.50, Avg60: 8.00, Avg300: 3.25, Total: 99999999},
+			Full: &PressureAvg{Avg10: 10.00, Avg60: 6.00, Avg300: 2.00, Total: 88888888},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %#v, got %#v", want, got)
+	}
+
+	cg := readCgroupPressure(fsys)
+	wantCG := &Pressure{
+		Cgroup: "/system.slice/agent.service",
+		CPU: &PressureStat{
+			Some: &PressureAvg{Avg10: 25, Avg60: 20, Avg300: 15, Total: 5000000},
+			Full: &PressureAvg{Avg10: 5, Avg60: 4, Avg300: 3, Total: 1000000},
+		},
+	}
+	if !reflect.DeepEqual(cg, wantCG) {
+		t.Fatalf("expected %#v, got %#v", wantCG, cg)
+	}
+
+	if p := readCgroupPressure(fstest.MapFS{}); p != nil {
+		t.Fatalf("expected nil, got %#v", p)
+	}
+}
+
+func TestReadCgroupPressure(t *testing.T) {
+	dir := t.TempDir()
+	err := os.WriteFile(filepath.Join(dir, "memory.pressure"),
+		[]byte("some avg10=0.00 avg60=0.00 avg300=0.00 total=7\nfull avg10=0.00 avg60=0.00 avg300=0.00 total=3\n"), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := ReadCgroupPressure(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.CPU != nil || p.Memory == nil || p.Memory.Some.Total != 7 || p.Memory.Full.Total != 3 {
+		t.Fatalf("unexpected pressure: %#v", p)
+	}
+
+	if _, err := ReadCgroupPressure(t.TempDir()); err == nil {
+		t.Fatal("expected an error")
+	}
+}
+
+func TestParsePressure(t *testing.T) {
+	for _, s := range []string{
+		"",
+		"some avg10\n",
+		"some avg10=x avg60=0.00 avg300=0.00 total=0\n",
+	} {
+		if _, err := parsePressure([]byte(s)); err == nil {
+			t.Fatalf("%q: expected an error", s)
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package sysinfo
+
+import (
+	"bytes"
+	"io/fs"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Runtime describes the host's load and uptime.
+//
+// On Linux, this information is read from /proc/loadavg,
+// /proc/uptime, and /proc/stat.
+type Runtime struct {
+	// Load1 is the load average over the last minute.
+	Load1 float64 `json:"load1"`
+	// Load5 is the load average over the last five minutes.
+	Load5 float64 `json:"load5"`
+	// Load15 is the load average over the last fifteen
+	// minutes.
+	Load15 float64 `json:"load15"`
+	// Runnable is the number of runnable tasks.
+	Runnable int `json:"runnable"`
+	// Tasks is the total number of tasks.
+	Tasks int `json:"tasks"`
+	// LastPID is the most recently created process ID.
+	LastPID int `json:"last_pid"`
+	// Uptime is the time in seconds since the host booted.
+	Uptime float64 `json:"uptime_s"`
+	// Idle is the total time in seconds every CPU has spent
+	// idle since the host booted. It can exceed Uptime on
+	// hosts with more than one CPU.
+	Idle float64 `json:"idle_s"`
+	// BootTime is when the host booted.
+	//
+	// Matches: btime
+	BootTime time.Time `json:"boot_time"`
+}
+
+// readRuntime reads /proc/loadavg, /proc/uptime, and the
+// btime line of /proc/stat.
+//
+// fsys should be rooted at "/".
+func readRuntime(fsys fs.FS) Runtime {
+	var r Runtime
+	parseLoadAvg(&r, readString(fsys, "proc/loadavg"))
+	if f := strings.Fields(readString(fsys, "proc/uptime")); len(f) == 2 {
+		r.Uptime = atof(f[0])
+		r.Idle = atof(f[1])
+	}
+	if buf, err := fs.ReadFile(fsys, "proc/stat"); err == nil {
+		r.BootTime = parseBootTime(buf)
+	}
+	return r
+}
+
+// parseLoadAvg parses /proc/loadavg.
+//
+// It should look like
+//
+//	0.20 0.18 0.12 1/80 11206
+//
+// where the fourth column is the number of runnable tasks and
+// the total number of tasks.
+func parseLoadAvg(r *Runtime, s string) {
+	f := strings.Fields(s)
+	if len(f) != 5 {
+		return
+	}
+	r.Load1 = atof(f[0])
+	r.Load5 = atof(f[1])
+	r.Load15 = atof(f[2])
+	if i := strings.IndexByte(f[3], '/'); i >= 0 {
+		r.Runnable = atoi(f[3][:i])
+		r.Tasks = atoi(f[3][i+1:])
+	}
+	r.LastPID = atoi(f[4])
+}
+
+// parseBootTime returns the btime line of /proc/stat, which
+// is the boot time in seconds since the Unix epoch.
+//
+// The intr line that precedes it can be hundreds of kilobytes
+// long on hosts with many interrupts.
+func parseBootTime(buf []byte) time.Time {
+	for len(buf) > 0 {
+		line := buf
+		if i := bytes.IndexByte(buf, '\n'); i >= 0 {
+			line, buf = buf[:i], buf[i+1:]
+		} else {
+			buf = nil
+		}
+		if !bytes.HasPrefix(line, []byte("btime")) {
+			continue
+		}
+		f := strings.Fields(string(line))
+		if len(f) != 2 || f[0] != "btime" {
+			continue
+		}
+		v, err := strconv.ParseInt(f[1], 10, 64)
+		if err != nil {
+			break
+		}
+		return time.Unix(v, 0).UTC()
+	}
+	return time.Time{}
+}
@@ -0,0 +1,43 @@
+package sysinfo
+
+import (
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+func TestReadRuntime(t *testing.T) {
+	fsys := fstest.MapFS{
+		"proc/loadavg": {Data: []byte("0.20 0.18 0.12 1/80 11206\n")},
+		"proc/uptime":  {Data: []byte("350735.47 234388.90\n")},
+		"proc/stat":    {Data: []byte("cpu  4705 356 584 3699 23 23 0 0 0 0\nintr 1 2 3\nctxt 1990473\nbtime 1062191376\nprocesses 2915\n")},
+	}
+	got := readRuntime(fsys)
+	want := Runtime{
+		Load1:    0.20,
+		Load5:    0.18,
+		Load15:   0.12,
+		Runnable: 1,
+		Tasks:    80,
+		LastPID:  11206,
+		Uptime:   350735.47,
+		Idle:     234388.90,
+		BootTime: time.Unix(1062191376, 0).UTC(),
+	}
+	if got != want {
+		t.Fatalf("expected %#v, got %#v", want, got)
+	}
+
+	// Hosts with many interrupts have an intr line longer
+	// than bufio.Scanner's default limit.
+	intr := "intr " + strings.Repeat("0 ", 80000)
+	fsys["proc/stat"] = &fstest.MapFile{Data: []byte("cpu  4705 356 584 3699 23 23 0 0 0 0\n" + intr + "\nbtime 1062191376\n")}
+	if got := readRuntime(fsys); !got.BootTime.Equal(want.BootTime) {
+		t.Fatalf("expected boot time %v, got %v", want.BootTime, got.BootTime)
+	}
+
+	if got := readRuntime(fstest.MapFS{}); got != (Runtime{}) {
+		t.Fatalf("expected zero value, got %#v", got)
+	}
+}
@@ -0,0 +1,21 @@
+package sysinfo
+
+import (
+	_ "embed"
+)
+
+// SchemaVersion is the version of the JSON encoding of Info,
+// which is reported in its schema_version field.
+//
+// It is incremented whenever a field is renamed or removed
+// or its type changes. Adding a field does not change it.
+const SchemaVersion = 1
+
+//go:embed schema.json
+var schema []byte
+
+// JSONSchema returns a JSON Schema document that describes
+// the JSON encoding of Info.
+func JSONSchema() []byte {
+	return append([]byte(nil), schema...)
+}
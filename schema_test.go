@@ -0,0 +1,308 @@
+package sysinfo
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"flag"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+var update = flag.Bool("update", false, "update schema.json")
+
+// TestJSONSchema checks that schema.json matches the Info
+// type. Any change to the JSON encoding of Info changes the
+// schema, so this test fails until schema.json is regenerated
+// with
+//
+//	go test -run 'TestJSONSchema$' -update
+//
+// If the change renames or removes a field or changes its
+// type, SchemaVersion must be incremented as well.
+func TestJSONSchema(t *testing.T) {
+	got, err := generateSchema()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *update {
+		if err := os.WriteFile("schema.json", got, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	if !bytes.Equal(got, JSONSchema()) {
+		t.Fatal("schema.json is out of date: run go test -run 'TestJSONSchema$' -update " +
+			"and increment SchemaVersion if a field was renamed or removed")
+	}
+}
+
+// TestJSONSchemaCompat checks that the encoding of Info only
+// uses properties described by the schema.
+func TestJSONSchemaCompat(t *testing.T) {
+	var s map[string]interface{}
+	if err := json.Unmarshal(JSONSchema(), &s); err != nil {
+		t.Fatal(err)
+	}
+	props := s["properties"].(map[string]interface{})
+	v := props["schema_version"].(map[string]interface{})["const"]
+	if v != float64(SchemaVersion) {
+		t.Fatalf("expected schema_version %d, got %v", SchemaVersion, v)
+	}
+
+	ents, err := os.ReadDir("testdata")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, e := range ents {
+		if strings.HasPrefix(e.Name(), "dmi_") {
+			continue
+		}
+		info := readTestInfo(t, e.Name())
+		info.SchemaVersion = SchemaVersion
+		buf, err := json.Marshal(info)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var doc interface{}
+		if err := json.Unmarshal(buf, &doc); err != nil {
+			t.Fatal(err)
+		}
+		checkSchema(t, s, s, "", doc)
+	}
+}
+
+// checkSchema checks that every object property in v is
+// described by the schema node.
+func checkSchema(t *testing.T, root, node map[string]interface{}, path string, v interface{}) {
+	t.Helper()
+	if alts, ok := node["anyOf"].([]interface{}); ok {
+		node = alts[0].(map[string]interface{})
+	}
+	if ref, ok := node["$ref"].(string); ok {
+		name := strings.TrimPrefix(ref, "#/$defs/")
+		node = root["$defs"].(map[string]interface{})[name].(map[string]interface{})
+	}
+	switch v := v.(type) {
+	case map[string]interface{}:
+		props, _ := node["properties"].(map[string]interface{})
+		extra, _ := node["additionalProperties"].(map[string]interface{})
+		for k, x := range v {
+			p, ok := props[k].(map[string]interface{})
+			if !ok {
+				p = extra
+			}
+			if p == nil {
+				t.Fatalf("%s.%s: not in schema", path, k)
+			}
+			checkSchema(t, root, p, path+"."+k, x)
+		}
+	case []interface{}:
+		items, _ := node["items"].(map[string]interface{})
+		if items == nil {
+			t.Fatalf("%s: not an array in schema", path)
+		}
+		for _, x := range v {
+			checkSchema(t, root, items, path+"[]", x)
+		}
+	}
+}
+
+// generateSchema generates the JSON Schema document for Info
+// from its type and doc comments.
+func generateSchema() ([]byte, error) {
+	docs, err := parseDocs(".")
+	if err != nil {
+		return nil, err
+	}
+	g := &schemaGen{
+		docs: docs,
+		defs: make(map[string]interface{}),
+	}
+	s := g.object(reflect.TypeOf(Info{}), "Info")
+	s["$schema"] = "https://json-schema.org/draft/2020-12/schema"
+	s["title"] = "sysinfo.Info"
+	s["$defs"] = g.defs
+	props := s["properties"].(map[string]interface{})
+	props["schema_version"].(map[string]interface{})["const"] = SchemaVersion
+	buf, err := json.MarshalIndent(s, "", "\t")
+	if err != nil {
+		return nil, err
+	}
+	return append(buf, '\n'), nil
+}
+
+type schemaGen struct {
+	// docs maps "Type" and "Type.Field" to doc comments.
+	docs map[string]string
+	defs map[string]interface{}
+}
+
+var (
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	timeType          = reflect.TypeOf(time.Time{})
+	pkgPath           = reflect.TypeOf(Info{}).PkgPath()
+)
+
+// schema returns the schema of a field of type t, where name
+// is the field's doc comment key.
+func (g *schemaGen) schema(t reflect.Type, name string) map[string]interface{} {
+	switch {
+	case t == timeType:
+		return map[string]interface{}{"type": "string", "format": "date-time"}
+	case t.Implements(textMarshalerType):
+		return map[string]interface{}{"type": "string"}
+	}
+	switch t.Kind() {
+	case reflect.Ptr:
+		return g.schema(t.Elem(), name)
+	case reflect.Struct:
+		if t.Name() == "" {
+			return g.object(t, name)
+		}
+		if t.PkgPath() == pkgPath {
+			if _, ok := g.defs[t.Name()]; !ok {
+				g.defs[t.Name()] = nil // break cycles
+				g.defs[t.Name()] = g.object(t, t.Name())
+			}
+			return map[string]interface{}{"$ref": "#/$defs/" + t.Name()}
+		}
+		return map[string]interface{}{"type": "object"}
+	case reflect.Slice, reflect.Array:
+		return map[string]interface{}{
+			"type":  "array",
+			"items": g.schema(t.Elem(), name),
+		}
+	case reflect.Map:
+		return map[string]interface{}{
+			"type":                 "object",
+			"additionalProperties": g.schema(t.Elem(), name),
+		}
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]interface{}{"type": "integer", "minimum": 0}
+	default:
+		return map[string]interface{}{"type": "integer"}
+	}
+}
+
+// object returns the schema of a struct, where name is its
+// doc comment key.
+func (g *schemaGen) object(t reflect.Type, name string) map[string]interface{} {
+	props := make(map[string]interface{})
+	var required []string
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" {
+			continue
+		}
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		key := fieldName(f)
+		s := g.schema(f.Type, name+"."+f.Name)
+		if !strings.Contains(tag, ",omitempty") {
+			required = append(required, key)
+			if k := f.Type.Kind(); (k == reflect.Ptr || k == reflect.Slice || k == reflect.Map) &&
+				!f.Type.Implements(textMarshalerType) {
+				// Nil values are encoded as null.
+				s = map[string]interface{}{
+					"anyOf": []interface{}{s, map[string]interface{}{"type": "null"}},
+				}
+			}
+		}
+		if doc := g.docs[name+"."+f.Name]; doc != "" {
+			s["description"] = doc
+		}
+		props[key] = s
+	}
+	sort.Strings(required)
+	s := map[string]interface{}{
+		"type":       "object",
+		"properties": props,
+	}
+	if len(required) > 0 {
+		s["required"] = required
+	}
+	if doc := g.docs[name]; doc != "" {
+		s["description"] = doc
+	}
+	return s
+}
+
+// parseDocs returns the doc comments of the struct types and
+// their fields in the package in dir.
+func parseDocs(dir string) (map[string]string, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return nil, err
+	}
+	docs := make(map[string]string)
+	fset := token.NewFileSet()
+	for _, name := range files {
+		if strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+		f, err := parser.ParseFile(fset, name, nil, parser.ParseComments)
+		if err != nil {
+			return nil, err
+		}
+		for _, decl := range f.Decls {
+			gd, ok := decl.(*ast.GenDecl)
+			if !ok || gd.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range gd.Specs {
+				ts := spec.(*ast.TypeSpec)
+				doc := ts.Doc
+				if doc == nil && len(gd.Specs) == 1 {
+					doc = gd.Doc
+				}
+				docs[ts.Name.Name] = docText(doc)
+				if st, ok := ts.Type.(*ast.StructType); ok {
+					fieldDocs(docs, ts.Name.Name, st)
+				}
+			}
+		}
+	}
+	return docs, nil
+}
+
+func fieldDocs(docs map[string]string, prefix string, st *ast.StructType) {
+	for _, f := range st.Fields.List {
+		for _, n := range f.Names {
+			key := prefix + "." + n.Name
+			docs[key] = docText(f.Doc)
+			if inner, ok := f.Type.(*ast.StructType); ok {
+				fieldDocs(docs, key, inner)
+			}
+		}
+	}
+}
+
+// docText returns the text of a doc comment with each
+// paragraph on a single line.
+func docText(g *ast.CommentGroup) string {
+	if g == nil {
+		return ""
+	}
+	var paras []string
+	for _, p := range strings.Split(strings.TrimSpace(g.Text()), "\n\n") {
+		paras = append(paras, strings.Join(strings.Fields(p), " "))
+	}
+	return strings.Join(paras, "\n\n")
+}
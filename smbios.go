@@ -0,0 +1,769 @@
+package sysinfo
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io/fs"
+	"strings"
+)
+
+// SMBIOS is a decoded SMBIOS structure table.
+//
+// On Linux, the table is read from /sys/firmware/dmi/tables,
+// which is usually only readable by root.
+//
+// See the DMTF System Management BIOS (SMBIOS) Reference
+// Specification, version 3.x.
+type SMBIOS struct {
+	// Version is the SMBIOS version from the entry point,
+	// for example "3.2.0".
+	Version string `json:"version,omitempty"`
+	// System is assembled from the BIOS (type 0), System (type
+	// 1), Baseboard (type 2), and Chassis (type 3) structures.
+	//
+	// Unlike Info.System, it includes fields that are usually
+	// only readable by root.
+	System System `json:"system"`
+	// Processors are the Processor (type 4) structures, one
+	// per socket.
+	Processors []Processor `json:"processors,omitempty"`
+	// MemoryArrays are the Physical Memory Array (type 16)
+	// structures.
+	MemoryArrays []MemoryArray `json:"memory_arrays,omitempty"`
+	// MemoryDevices are the Memory Device (type 17)
+	// structures, one per DIMM slot.
+	MemoryDevices []MemoryDevice `json:"memory_devices,omitempty"`
+	// Slots are the System Slots (type 9) structures.
+	Slots []Slot `json:"slots,omitempty"`
+	// Structures is every structure in the table, including
+	// the ones decoded above.
+	Structures []SMBIOSStructure `json:"-"`
+}
+
+// SMBIOSStructure is a single raw SMBIOS structure.
+type SMBIOSStructure struct {
+	// Type is the structure type.
+	Type uint8
+	// Handle is the structure's unique handle.
+	Handle uint16
+	// Data is the formatted area of the structure, including
+	// the four byte header.
+	Data []byte
+	// Strings is the structure's string set.
+	//
+	// SMBIOS string numbers are one-indexed, so string
+	// number n is Strings[n-1].
+	Strings []string
+}
+
+// Processor describes a processor socket.
+//
+// Each field has a "Offset:" comment describing its offset in
+// the SMBIOS structure.
+type Processor struct {
+	// Handle is the structure's handle.
+	Handle uint16 `json:"handle"`
+	// Socket is the socket's reference designation.
+	//
+	// Offset: 0x04
+	Socket string `json:"socket,omitempty"`
+	// Manufacturer is the processor manufacturer.
+	//
+	// Offset: 0x07
+	Manufacturer string `json:"manufacturer,omitempty"`
+	// ID is the raw processor ID, which is CPUID leaf 1 on
+	// x86 and MIDR_EL1 on ARM.
+	//
+	// Offset: 0x08
+	ID uint64 `json:"id,omitempty"`
+	// Version is the processor version, usually the brand
+	// string.
+	//
+	// Offset: 0x10
+	Version string `json:"version,omitempty"`
+	// ExternalClock is the external clock frequency in MHz.
+	//
+	// Offset: 0x12
+	ExternalClock int `json:"external_clock_mhz,omitempty"`
+	// MaxSpeed is the maximum speed supported by the socket
+	// in MHz.
+	//
+	// Offset: 0x14
+	MaxSpeed int `json:"max_speed_mhz,omitempty"`
+	// CurrentSpeed is the speed at boot in MHz.
+	//
+	// Offset: 0x16
+	CurrentSpeed int `json:"current_speed_mhz,omitempty"`
+	// Populated is whether the socket is populated.
+	//
+	// Offset: 0x18
+	Populated bool `json:"populated"`
+	// Serial is the processor serial number.
+	//
+	// Offset: 0x20
+	Serial string `json:"serial,omitempty"`
+	// PartNumber is the processor part number.
+	//
+	// Offset: 0x22
+	PartNumber string `json:"part_number,omitempty"`
+	// Cores is the number of cores.
+	//
+	// Offset: 0x23, 0x2a
+	Cores int `json:"cores,omitempty"`
+	// CoresEnabled is the number of enabled cores.
+	//
+	// Offset: 0x24, 0x2c
+	CoresEnabled int `json:"cores_enabled,omitempty"`
+	// Threads is the number of threads.
+	//
+	// Offset: 0x25, 0x2e
+	Threads int `json:"threads,omitempty"`
+}
+
+// MemoryArray describes a collection of memory devices.
+type MemoryArray struct {
+	// Handle is the structure's handle.
+	Handle uint16 `json:"handle"`
+	// Location is where the array is located.
+	//
+	// Typically 3, the system board.
+	//
+	// Offset: 0x04
+	Location uint8 `json:"location,omitempty"`
+	// Use is the function of the array.
+	//
+	// Typically 3, system memory.
+	//
+	// Offset: 0x05
+	Use uint8 `json:"use,omitempty"`
+	// ECC is the array's error correction type.
+	//
+	// Offset: 0x06
+	ECC MemoryECC `json:"ecc,omitempty"`
+	// MaxCapacity is the maximum memory capacity in bytes.
+	//
+	// Offset: 0x07, 0x0f
+	MaxCapacity uint64 `json:"max_capacity,omitempty"`
+	// Devices is the number of memory device slots.
+	//
+	// Offset: 0x0d
+	Devices int `json:"num_devices,omitempty"`
+}
+
+// MemoryDevice describes a memory device slot, typically a
+// DIMM.
+type MemoryDevice struct {
+	// Handle is the structure's handle.
+	Handle uint16 `json:"handle"`
+	// ArrayHandle is the handle of the MemoryArray the device
+	// belongs to.
+	//
+	// Offset: 0x04
+	ArrayHandle uint16 `json:"array_handle"`
+	// TotalWidth is the total width in bits, including ECC
+	// bits.
+	//
+	// Offset: 0x08
+	TotalWidth int `json:"total_width,omitempty"`
+	// DataWidth is the data width in bits.
+	//
+	// Offset: 0x0a
+	DataWidth int `json:"data_width,omitempty"`
+	// Size is the size of the device in bytes.
+	//
+	// Size is zero if the slot is empty.
+	//
+	// Offset: 0x0c, 0x1c
+	Size uint64 `json:"size,omitempty"`
+	// FormFactor is the device's form factor.
+	//
+	// Offset: 0x0e
+	FormFactor MemoryFormFactor `json:"form_factor,omitempty"`
+	// Locator identifies the socket, for example "DIMM_A1".
+	//
+	// Offset: 0x10
+	Locator string `json:"locator,omitempty"`
+	// BankLocator identifies the bank, for example "BANK 0".
+	//
+	// Offset: 0x11
+	BankLocator string `json:"bank_locator,omitempty"`
+	// Type is the type of memory.
+	//
+	// Offset: 0x12
+	Type MemoryType `json:"type,omitempty"`
+	// Speed is the maximum speed in MT/s.
+	//
+	// Offset: 0x15, 0x54
+	Speed int `json:"speed_mts,omitempty"`
+	// Manufacturer is the device manufacturer.
+	//
+	// Offset: 0x17
+	Manufacturer string `json:"manufacturer,omitempty"`
+	// Serial is the device serial number.
+	//
+	// Offset: 0x18
+	Serial string `json:"serial,omitempty"`
+	// AssetTag is the device's asset tag.
+	//
+	// Offset: 0x19
+	AssetTag string `json:"asset_tag,omitempty"`
+	// PartNumber is the device part number.
+	//
+	// Offset: 0x1a
+	PartNumber string `json:"part_number,omitempty"`
+	// Rank is the number of ranks.
+	//
+	// Offset: 0x1b
+	Rank int `json:"rank,omitempty"`
+	// ConfiguredSpeed is the configured speed in MT/s.
+	//
+	// Offset: 0x20, 0x58
+	ConfiguredSpeed int `json:"configured_speed_mts,omitempty"`
+	// ConfiguredVoltage is the configured voltage in
+	// millivolts.
+	//
+	// Offset: 0x26
+	ConfiguredVoltage int `json:"configured_voltage_mv,omitempty"`
+}
+
+// Populated reports whether the memory device slot is
+// populated.
+func (m MemoryDevice) Populated() bool {
+	return m.Size != 0
+}
+
+// Slot describes a system expansion slot.
+type Slot struct {
+	// Handle is the structure's handle.
+	Handle uint16 `json:"handle"`
+	// Designation is the slot's reference designation, for
+	// example "PCIE1".
+	//
+	// Offset: 0x04
+	Designation string `json:"designation,omitempty"`
+	// Type is the slot type.
+	//
+	// Offset: 0x05
+	Type SlotType `json:"type,omitempty"`
+	// Usage is the slot's current usage.
+	//
+	// Offset: 0x07
+	Usage SlotUsage `json:"usage,omitempty"`
+	// ID is the slot identifier.
+	//
+	// Offset: 0x09
+	ID int `json:"id,omitempty"`
+	// Segment is the PCI segment group number.
+	//
+	// Offset: 0x0d
+	Segment int `json:"segment,omitempty"`
+	// Bus is the PCI bus number.
+	//
+	// Offset: 0x0f
+	Bus int `json:"bus,omitempty"`
+	// Device is the PCI device number.
+	//
+	// Offset: 0x10
+	Device int `json:"device,omitempty"`
+	// Function is the PCI function number.
+	//
+	// Offset: 0x10
+	Function int `json:"function,omitempty"`
+}
+
+// MemoryType is an SMBIOS memory device type.
+//
+// See the SMBIOS specification, section 7.18.2.
+type MemoryType uint8
+
+const (
+	MemoryOther       MemoryType = 0x01 // Other
+	MemoryUnknown     MemoryType = 0x02 // Unknown
+	MemoryDRAM        MemoryType = 0x03 // DRAM
+	MemoryEDRAM       MemoryType = 0x04 // EDRAM
+	MemoryVRAM        MemoryType = 0x05 // VRAM
+	MemorySRAM        MemoryType = 0x06 // SRAM
+	MemoryRAM         MemoryType = 0x07 // RAM
+	MemoryROM         MemoryType = 0x08 // ROM
+	MemoryFlash       MemoryType = 0x09 // Flash
+	MemoryEEPROM      MemoryType = 0x0a // EEPROM
+	MemoryFEPROM      MemoryType = 0x0b // FEPROM
+	MemoryEPROM       MemoryType = 0x0c // EPROM
+	MemoryCDRAM       MemoryType = 0x0d // CDRAM
+	Memory3DRAM       MemoryType = 0x0e // 3DRAM
+	MemorySDRAM       MemoryType = 0x0f // SDRAM
+	MemorySGRAM       MemoryType = 0x10 // SGRAM
+	MemoryRDRAM       MemoryType = 0x11 // RDRAM
+	MemoryDDR         MemoryType = 0x12 // DDR
+	MemoryDDR2        MemoryType = 0x13 // DDR2
+	MemoryDDR2FBDIMM  MemoryType = 0x14 // DDR2 FB-DIMM
+	MemoryDDR3        MemoryType = 0x18 // DDR3
+	MemoryFBD2        MemoryType = 0x19 // FBD2
+	MemoryDDR4        MemoryType = 0x1a // DDR4
+	MemoryLPDDR       MemoryType = 0x1b // LPDDR
+	MemoryLPDDR2      MemoryType = 0x1c // LPDDR2
+	MemoryLPDDR3      MemoryType = 0x1d // LPDDR3
+	MemoryLPDDR4      MemoryType = 0x1e // LPDDR4
+	MemoryNonVolatile MemoryType = 0x1f // Logical non-volatile device
+	MemoryHBM         MemoryType = 0x20 // HBM
+	MemoryHBM2        MemoryType = 0x21 // HBM2
+	MemoryDDR5        MemoryType = 0x22 // DDR5
+	MemoryLPDDR5      MemoryType = 0x23 // LPDDR5
+	MemoryHBM3        MemoryType = 0x24 // HBM3
+)
+
+func (t MemoryType) MarshalText() ([]byte, error) {
+	return []byte(t.String()), nil
+}
+
+func (t *MemoryType) UnmarshalText(text []byte) error {
+	v, err := parseEnum("MemoryType", string(text), func(i uint8) string {
+		return MemoryType(i).String()
+	})
+	if err != nil {
+		return err
+	}
+	*t = MemoryType(v)
+	return nil
+}
+
+// MemoryFormFactor is an SMBIOS memory device form factor.
+//
+// See the SMBIOS specification, section 7.18.1.
+type MemoryFormFactor uint8
+
+const (
+	FormFactorOther      MemoryFormFactor = 0x01 // Other
+	FormFactorUnknown    MemoryFormFactor = 0x02 // Unknown
+	FormFactorSIMM       MemoryFormFactor = 0x03 // SIMM
+	FormFactorSIP        MemoryFormFactor = 0x04 // SIP
+	FormFactorChip       MemoryFormFactor = 0x05 // Chip
+	FormFactorDIP        MemoryFormFactor = 0x06 // DIP
+	FormFactorZIP        MemoryFormFactor = 0x07 // ZIP
+	FormFactorCard       MemoryFormFactor = 0x08 // Proprietary Card
+	FormFactorDIMM       MemoryFormFactor = 0x09 // DIMM
+	FormFactorTSOP       MemoryFormFactor = 0x0a // TSOP
+	FormFactorRowOfChips MemoryFormFactor = 0x0b // Row of chips
+	FormFactorRIMM       MemoryFormFactor = 0x0c // RIMM
+	FormFactorSODIMM     MemoryFormFactor = 0x0d // SODIMM
+	FormFactorSRIMM      MemoryFormFactor = 0x0e // SRIMM
+	FormFactorFBDIMM     MemoryFormFactor = 0x0f // FB-DIMM
+	FormFactorDie        MemoryFormFactor = 0x10 // Die
+)
+
+func (f MemoryFormFactor) MarshalText() ([]byte, error) {
+	return []byte(f.String()), nil
+}
+
+func (f *MemoryFormFactor) UnmarshalText(text []byte) error {
+	v, err := parseEnum("MemoryFormFactor", string(text), func(i uint8) string {
+		return MemoryFormFactor(i).String()
+	})
+	if err != nil {
+		return err
+	}
+	*f = MemoryFormFactor(v)
+	return nil
+}
+
+// MemoryECC is an SMBIOS memory array error correction type.
+//
+// See the SMBIOS specification, section 7.17.3.
+type MemoryECC uint8
+
+const (
+	ECCOther     MemoryECC = 0x01 // Other
+	ECCUnknown   MemoryECC = 0x02 // Unknown
+	ECCNone      MemoryECC = 0x03 // None
+	ECCParity    MemoryECC = 0x04 // Parity
+	ECCSingleBit MemoryECC = 0x05 // Single-bit ECC
+	ECCMultiBit  MemoryECC = 0x06 // Multi-bit ECC
+	ECCCRC       MemoryECC = 0x07 // CRC
+)
+
+func (e MemoryECC) MarshalText() ([]byte, error) {
+	return []byte(e.String()), nil
+}
+
+func (e *MemoryECC) UnmarshalText(text []byte) error {
+	v, err := parseEnum("MemoryECC", string(text), func(i uint8) string {
+		return MemoryECC(i).String()
+	})
+	if err != nil {
+		return err
+	}
+	*e = MemoryECC(v)
+	return nil
+}
+
+// SlotUsage is an SMBIOS system slot's current usage.
+//
+// See the SMBIOS specification, section 7.10.3.
+type SlotUsage uint8
+
+const (
+	SlotOther       SlotUsage = 0x01 // Other
+	SlotUnknown     SlotUsage = 0x02 // Unknown
+	SlotAvailable   SlotUsage = 0x03 // Available
+	SlotInUse       SlotUsage = 0x04 // In use
+	SlotUnavailable SlotUsage = 0x05 // Unavailable
+)
+
+func (u SlotUsage) MarshalText() ([]byte, error) {
+	return []byte(u.String()), nil
+}
+
+func (u *SlotUsage) UnmarshalText(text []byte) error {
+	v, err := parseEnum("SlotUsage", string(text), func(i uint8) string {
+		return SlotUsage(i).String()
+	})
+	if err != nil {
+		return err
+	}
+	*u = SlotUsage(v)
+	return nil
+}
+
+// SlotType is an SMBIOS system slot type.
+//
+// See the SMBIOS specification, section 7.10.1.
+type SlotType uint8
+
+func (t SlotType) String() string {
+	switch t {
+	case 0x01:
+		return "Other"
+	case 0x02:
+		return "Unknown"
+	case 0x03:
+		return "ISA"
+	case 0x06:
+		return "PCI"
+	case 0x0e:
+		return "PCI-66"
+	case 0x0f:
+		return "AGP"
+	case 0x12:
+		return "PCI-X"
+	case 0x13:
+		return "AGP 8X"
+	case 0x14:
+		return "M.2 Socket 1-DP"
+	case 0x15:
+		return "M.2 Socket 1-SD"
+	case 0x16:
+		return "M.2 Socket 2"
+	case 0x17:
+		return "M.2 Socket 3"
+	case 0x1f:
+		return "PCI Express Gen 2 U.2"
+	case 0x20:
+		return "PCI Express Gen 3 U.2"
+	case 0x24:
+		return "PCI Express Gen 4 U.2"
+	case 0x25:
+		return "PCI Express Gen 5 U.2"
+	}
+	// PCI Express, then widths x1 through x16, for each
+	// generation.
+	gens := []struct {
+		base SlotType
+		name string
+	}{
+		{0xa5, "PCI Express"},
+		{0xab, "PCI Express Gen 2"},
+		{0xb1, "PCI Express Gen 3"},
+		{0xb8, "PCI Express Gen 4"},
+		{0xbe, "PCI Express Gen 5"},
+	}
+	widths := []string{"", " x1", " x2", " x4", " x8", " x16"}
+	for _, g := range gens {
+		if t >= g.base && int(t-g.base) < len(widths) {
+			return g.name + widths[t-g.base]
+		}
+	}
+	return fmt.Sprintf("SlotType(%#x)", uint8(t))
+}
+
+func (t SlotType) MarshalText() ([]byte, error) {
+	return []byte(t.String()), nil
+}
+
+func (t *SlotType) UnmarshalText(text []byte) error {
+	v, err := parseEnum("SlotType", string(text), func(i uint8) string {
+		return SlotType(i).String()
+	})
+	if err != nil {
+		return err
+	}
+	*t = SlotType(v)
+	return nil
+}
+
+// readSMBIOS reads and decodes /sys/firmware/dmi/tables.
+//
+// fsys should be rooted at "/".
+func readSMBIOS(fsys fs.FS) (*SMBIOS, error) {
+	const dir = "sys/firmware/dmi/tables/"
+	buf, err := fs.ReadFile(fsys, dir+"DMI")
+	if err != nil {
+		return nil, err
+	}
+	s, err := DecodeSMBIOS(buf)
+	if err != nil {
+		return nil, err
+	}
+	if ep, err := fs.ReadFile(fsys, dir+"smbios_entry_point"); err == nil {
+		s.Version = smbiosVersion(ep)
+	}
+	return s, nil
+}
+
+// smbiosVersion returns the version from an SMBIOS entry
+// point structure.
+func smbiosVersion(ep []byte) string {
+	switch {
+	case len(ep) >= 10 && string(ep[:5]) == "_SM3_":
+		return fmt.Sprintf("%d.%d.%d", ep[7], ep[8], ep[9])
+	case len(ep) >= 8 && string(ep[:4]) == "_SM_":
+		return fmt.Sprintf("%d.%d", ep[6], ep[7])
+	default:
+		return ""
+	}
+}
+
+var errSMBIOSTruncated = errors.New("sysinfo: truncated SMBIOS table")
+
+// DecodeSMBIOS decodes an SMBIOS structure table, such as the
+// contents of /sys/firmware/dmi/tables/DMI.
+//
+// Decoding stops at the End-of-Table (type 127) structure or
+// the end of buf, whichever comes first.
+func DecodeSMBIOS(buf []byte) (*SMBIOS, error) {
+	s := &SMBIOS{}
+	for len(buf) > 0 {
+		if len(buf) < 4 {
+			return nil, errSMBIOSTruncated
+		}
+		n := int(buf[1])
+		if n < 4 || n > len(buf) {
+			return nil, errSMBIOSTruncated
+		}
+		st := SMBIOSStructure{
+			Type:   buf[0],
+			Handle: binary.LittleEndian.Uint16(buf[2:]),
+			Data:   buf[:n],
+		}
+		// The string set is terminated by two NULs.
+		end := bytes.Index(buf[n:], []byte{0, 0})
+		if end < 0 {
+			return nil, errSMBIOSTruncated
+		}
+		if end > 0 {
+			st.Strings = strings.Split(string(buf[n:n+end]), "\x00")
+		}
+		buf = buf[n+end+2:]
+
+		s.Structures = append(s.Structures, st)
+		s.decode(st)
+		if st.Type == 127 {
+			break
+		}
+	}
+	return s, nil
+}
+
+func (s *SMBIOS) decode(st SMBIOSStructure) {
+	r := smbiosReader(st)
+	switch st.Type {
+	case 0:
+		b := &s.System.BIOS
+		b.Vendor = r.str(0x04)
+		b.Version = r.str(0x05)
+		b.Date = r.str(0x08)
+		if len(st.Data) > 0x15 && st.Data[0x14] != 0xff {
+			b.Release = fmt.Sprintf("%d.%d", st.Data[0x14], st.Data[0x15])
+		}
+	case 1:
+		y := &s.System
+		y.Vendor = r.str(0x04)
+		y.Product = r.str(0x05)
+		y.Version = r.str(0x06)
+		y.Serial = r.str(0x07)
+		y.UUID = r.uuid(0x08)
+		y.SKU = r.str(0x19)
+		y.Family = r.str(0x1a)
+	case 2:
+		s.System.Board = BaseBoard{
+			Vendor:   r.str(0x04),
+			Name:     r.str(0x05),
+			Version:  r.str(0x06),
+			Serial:   r.str(0x07),
+			AssetTag: r.str(0x08),
+		}
+	case 3:
+		s.System.Chassis = Chassis{
+			Vendor:   r.str(0x04),
+			Type:     ChassisType(r.byte(0x05) & 0x7f),
+			Version:  r.str(0x06),
+			Serial:   r.str(0x07),
+			AssetTag: r.str(0x08),
+		}
+	case 4:
+		p := Processor{
+			Handle:        st.Handle,
+			Socket:        r.str(0x04),
+			Manufacturer:  r.str(0x07),
+			ID:            r.qword(0x08),
+			Version:       r.str(0x10),
+			ExternalClock: int(r.word(0x12)),
+			MaxSpeed:      int(r.word(0x14)),
+			CurrentSpeed:  int(r.word(0x16)),
+			Populated:     r.byte(0x18)&0x40 != 0,
+			Serial:        r.str(0x20),
+			PartNumber:    r.str(0x22),
+			Cores:         int(r.byte(0x23)),
+			CoresEnabled:  int(r.byte(0x24)),
+			Threads:       int(r.byte(0x25)),
+		}
+		if p.Cores == 0xff {
+			p.Cores = int(r.word(0x2a))
+		}
+		if p.CoresEnabled == 0xff {
+			p.CoresEnabled = int(r.word(0x2c))
+		}
+		if p.Threads == 0xff {
+			p.Threads = int(r.word(0x2e))
+		}
+		s.Processors = append(s.Processors, p)
+	case 9:
+		devfn := r.byte(0x10)
+		s.Slots = append(s.Slots, Slot{
+			Handle:      st.Handle,
+			Designation: r.str(0x04),
+			Type:        SlotType(r.byte(0x05)),
+			Usage:       SlotUsage(r.byte(0x07)),
+			ID:          int(r.word(0x09)),
+			Segment:     int(r.word(0x0d)),
+			Bus:         int(r.byte(0x0f)),
+			Device:      int(devfn >> 3),
+			Function:    int(devfn & 0x7),
+		})
+	case 16:
+		a := MemoryArray{
+			Handle:      st.Handle,
+			Location:    r.byte(0x04),
+			Use:         r.byte(0x05),
+			ECC:         MemoryECC(r.byte(0x06)),
+			MaxCapacity: uint64(r.dword(0x07)) * 1024,
+			Devices:     int(r.word(0x0d)),
+		}
+		if r.dword(0x07) == 0x80000000 {
+			a.MaxCapacity = r.qword(0x0f)
+		}
+		s.MemoryArrays = append(s.MemoryArrays, a)
+	case 17:
+		d := MemoryDevice{
+			Handle:            st.Handle,
+			ArrayHandle:       r.word(0x04),
+			TotalWidth:        int(r.word(0x08)),
+			DataWidth:         int(r.word(0x0a)),
+			FormFactor:        MemoryFormFactor(r.byte(0x0e)),
+			Locator:           r.str(0x10),
+			BankLocator:       r.str(0x11),
+			Type:              MemoryType(r.byte(0x12)),
+			Speed:             int(r.word(0x15)),
+			Manufacturer:      r.str(0x17),
+			Serial:            r.str(0x18),
+			AssetTag:          r.str(0x19),
+			PartNumber:        r.str(0x1a),
+			Rank:              int(r.byte(0x1b) & 0x0f),
+			ConfiguredSpeed:   int(r.word(0x20)),
+			ConfiguredVoltage: int(r.word(0x26)),
+		}
+		switch size := r.word(0x0c); {
+		case size == 0xffff:
+			// Unknown.
+		case size == 0x7fff:
+			d.Size = uint64(r.dword(0x1c)&0x7fffffff) << 20
+		case size&0x8000 != 0:
+			d.Size = uint64(size&0x7fff) << 10
+		default:
+			d.Size = uint64(size) << 20
+		}
+		if d.TotalWidth == 0xffff {
+			d.TotalWidth = 0
+		}
+		if d.DataWidth == 0xffff {
+			d.DataWidth = 0
+		}
+		if d.Speed == 0xffff {
+			d.Speed = int(r.dword(0x54))
+		}
+		if d.ConfiguredSpeed == 0xffff {
+			d.ConfiguredSpeed = int(r.dword(0x58))
+		}
+		s.MemoryDevices = append(s.MemoryDevices, d)
+	}
+}
+
+// smbiosReader reads fields from a structure's formatted area.
+//
+// Fields past the end of the formatted area, which occur when
+// the firmware implements an older version of the
+// specification, read as zero.
+type smbiosReader SMBIOSStructure
+
+func (r smbiosReader) byte(off int) uint8 {
+	if off >= len(r.Data) {
+		return 0
+	}
+	return r.Data[off]
+}
+
+func (r smbiosReader) word(off int) uint16 {
+	if off+2 > len(r.Data) {
+		return 0
+	}
+	return binary.LittleEndian.Uint16(r.Data[off:])
+}
+
+func (r smbiosReader) dword(off int) uint32 {
+	if off+4 > len(r.Data) {
+		return 0
+	}
+	return binary.LittleEndian.Uint32(r.Data[off:])
+}
+
+func (r smbiosReader) qword(off int) uint64 {
+	if off+8 > len(r.Data) {
+		return 0
+	}
+	return binary.LittleEndian.Uint64(r.Data[off:])
+}
+
+// str returns the string whose number is at off.
+func (r smbiosReader) str(off int) string {
+	n := int(r.byte(off))
+	if n == 0 || n > len(r.Strings) {
+		return ""
+	}
+	return strings.TrimSpace(r.Strings[n-1])
+}
+
+// uuid returns the UUID at off in the same form as
+// /sys/class/dmi/id/product_uuid.
+func (r smbiosReader) uuid(off int) string {
+	if off+16 > len(r.Data) {
+		return ""
+	}
+	b := r.Data[off : off+16]
+	// The first three fields are little endian.
+	return fmt.Sprintf("%08x-%04x-%04x-%x-%x",
+		binary.LittleEndian.Uint32(b[0:]),
+		binary.LittleEndian.Uint16(b[4:]),
+		binary.LittleEndian.Uint16(b[6:]),
+		b[8:10], b[10:16])
+}
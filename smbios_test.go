@@ -0,0 +1,177 @@
+package sysinfo
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestDecodeSMBIOS(t *testing.T) {
+	buf, err := os.ReadFile(filepath.Join("testdata", "dmi_supermicro_epyc"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := DecodeSMBIOS(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := len(got.Structures); n != 14 {
+		t.Fatalf("expected 14 structures, got %d", n)
+	}
+
+	wantSys := System{
+		Vendor:  "Supermicro",
+		Product: "AS -1023US-TR4",
+		Version: "0123456789",
+		Serial:  "A123456789",
+		UUID:    "00000000-0000-0000-0000-ac1f6b8e2b3c",
+		SKU:     "To be filled by O.E.M.",
+		Family:  "Server",
+		Board: BaseBoard{
+			Vendor:   "Supermicro",
+			Name:     "H11DSU-iN",
+			Version:  "1.02A",
+			Serial:   "ZM19AS000000",
+			AssetTag: "To be filled by O.E.M.",
+		},
+		BIOS: BIOS{
+			Vendor:  "American Megatrends Inc.",
+			Version: "2.1",
+			Date:    "06/14/2019",
+			Release: "5.14",
+		},
+		Chassis: Chassis{
+			Type:     ChassisRackMount,
+			Vendor:   "Supermicro",
+			Version:  "0123456789",
+			Serial:   "C8150LK00000000",
+			AssetTag: "To be filled by O.E.M.",
+		},
+	}
+	if !reflect.DeepEqual(got.System, wantSys) {
+		t.Fatalf("expected %#v, got %#v", wantSys, got.System)
+	}
+
+	wantProcs := []Processor{
+		{
+			Handle:        0x0004,
+			Socket:        "CPU1",
+			Manufacturer:  "Advanced Micro Devices, Inc.",
+			ID:            0x178bfbff00800f12,
+			Version:       "AMD EPYC 7551 32-Core Processor",
+			ExternalClock: 100,
+			MaxSpeed:      3600,
+			CurrentSpeed:  2000,
+			Populated:     true,
+			Cores:         32,
+			CoresEnabled:  32,
+			Threads:       64,
+		},
+		{
+			Handle: 0x0005,
+			Socket: "CPU2",
+		},
+	}
+	if !reflect.DeepEqual(got.Processors, wantProcs) {
+		t.Fatalf("expected %#v, got %#v", wantProcs, got.Processors)
+	}
+
+	wantArrays := []MemoryArray{
+		{Handle: 0x0010, Location: 3, Use: 3, ECC: ECCMultiBit, MaxCapacity: 2 << 40, Devices: 4},
+	}
+	if !reflect.DeepEqual(got.MemoryArrays, wantArrays) {
+		t.Fatalf("expected %#v, got %#v", wantArrays, got.MemoryArrays)
+	}
+
+	dimm := func(h uint16, loc, bank string, size uint64) MemoryDevice {
+		return MemoryDevice{
+			Handle:            h,
+			ArrayHandle:       0x0010,
+			TotalWidth:        72,
+			DataWidth:         64,
+			Size:              size,
+			FormFactor:        FormFactorDIMM,
+			Locator:           loc,
+			BankLocator:       bank,
+			Type:              MemoryDDR4,
+			Speed:             2666,
+			Manufacturer:      "Samsung",
+			Serial:            "12345678",
+			AssetTag:          "Not Specified",
+			PartNumber:        "M393A4K40CB2-CTD",
+			Rank:              2,
+			ConfiguredSpeed:   2666,
+			ConfiguredVoltage: 1200,
+		}
+	}
+	empty := func(h uint16, loc, bank string) MemoryDevice {
+		return MemoryDevice{
+			Handle:       h,
+			ArrayHandle:  0x0010,
+			FormFactor:   FormFactorDIMM,
+			Locator:      loc,
+			BankLocator:  bank,
+			Type:         MemoryUnknown,
+			Manufacturer: "NO DIMM",
+			Serial:       "NO DIMM",
+			AssetTag:     "NO DIMM",
+			PartNumber:   "NO DIMM",
+		}
+	}
+	wantDIMMs := []MemoryDevice{
+		dimm(0x0011, "DIMMA1", "P0_Node0_Channel0_Dimm0", 32<<30),
+		empty(0x0012, "DIMMA2", "P0_Node0_Channel0_Dimm1"),
+		dimm(0x0013, "DIMMB1", "P0_Node0_Channel1_Dimm0", 16<<30),
+		empty(0x0014, "DIMMB2", "P0_Node0_Channel1_Dimm1"),
+	}
+	if !reflect.DeepEqual(got.MemoryDevices, wantDIMMs) {
+		t.Fatalf("expected %#v, got %#v", wantDIMMs, got.MemoryDevices)
+	}
+	for i, d := range got.MemoryDevices {
+		if d.Populated() != (i%2 == 0) {
+			t.Fatalf("#%d: unexpected Populated: %t", i, d.Populated())
+		}
+	}
+
+	wantSlots := []Slot{
+		{Handle: 0x0009, Designation: "RSC-R1UW-E8R SLOT1 PCI-E X16", Type: 0xb6, Usage: SlotInUse, ID: 1, Bus: 0x21},
+		{Handle: 0x000a, Designation: "RSC-W-66G4 SLOT2 PCI-E X16", Type: 0xbd, Usage: SlotAvailable, ID: 2, Bus: 0xff, Device: 0x1f, Function: 0x7},
+	}
+	if !reflect.DeepEqual(got.Slots, wantSlots) {
+		t.Fatalf("expected %#v, got %#v", wantSlots, got.Slots)
+	}
+	if s := got.Slots[0].Type.String(); s != "PCI Express Gen 3 x16" {
+		t.Fatalf("unexpected slot type: %q", s)
+	}
+	if s := got.Slots[1].Type.String(); s != "PCI Express Gen 4 x16" {
+		t.Fatalf("unexpected slot type: %q", s)
+	}
+}
+
+func TestDecodeSMBIOSTruncated(t *testing.T) {
+	buf, err := os.ReadFile(filepath.Join("testdata", "dmi_supermicro_epyc"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, n := range []int{1, 3, 10, 0x1a, 0x1a + 5} {
+		if _, err := DecodeSMBIOS(buf[:n]); err == nil {
+			t.Fatalf("%d: expected an error", n)
+		}
+	}
+}
+
+func TestSMBIOSVersion(t *testing.T) {
+	for _, tc := range []struct {
+		ep   []byte
+		want string
+	}{
+		{[]byte("_SM3_\x00\x18\x03\x02\x00\x01"), "3.2.0"},
+		{[]byte("_SM_\x00\x1f\x02\x08"), "2.8"},
+		{[]byte("_DMI_"), ""},
+	} {
+		if got := smbiosVersion(tc.ep); got != tc.want {
+			t.Fatalf("%q: expected %q, got %q", tc.ep, tc.want, got)
+		}
+	}
+}
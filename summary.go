@@ -0,0 +1,133 @@
+package sysinfo
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Summary is a compact description of the host's CPUs.
+//
+// Hosts usually have many identical CPUs that only differ by
+// their Proc, APICID, and CoreID fields. Summary groups them
+// into classes, so a 128-thread host with a single kind of
+// CPU has a single class.
+type Summary struct {
+	// CPUs is the number of CPUs in Classes.
+	CPUs int `json:"cpus"`
+	// Classes are the distinct kinds of CPU, ordered by
+	// their lowest CPU number.
+	Classes []CPUClass `json:"classes"`
+	// Offline is the set of offline CPUs that are not
+	// described, since /proc/cpuinfo does not list offline
+	// CPUs. They are not counted in CPUs or in any class.
+	Offline CPUSet `json:"offline"`
+}
+
+// CPUClass is a group of CPUs with identical models,
+// features, and caches.
+//
+// Each field other than CPUs and Count has the same meaning
+// and JSON name as the CPU field of the same name, except
+// that Cache is nil if the cache sizes are not known.
+type CPUClass struct {
+	// CPUs is the set of CPUs in the class.
+	CPUs CPUSet `json:"cpus"`
+	// Count is the number of CPUs in the class.
+	Count int `json:"count"`
+
+	VendorID  string      `json:"vendor_id,omitempty"`
+	Impl      Implementer `json:"implementer,omitempty"`
+	Arch      int         `json:"arch,omitempty"`
+	Variant   int         `json:"variant,omitempty"`
+	Part      Part        `json:"part_number,omitempty"`
+	Family    int         `json:"family,omitempty"`
+	Model     int         `json:"model_number,omitempty"`
+	Rev       int         `json:"revision,omitempty"`
+	ModelName string      `json:"model_name,omitempty"`
+	MicroArch string      `json:"micro_arch,omitempty"`
+	Features  []string    `json:"features,omitempty"`
+	Cache     *Cache      `json:"cache,omitempty"`
+}
+
+// String returns the class in the form "Cortex-A55 ×4 (0-3)".
+func (c CPUClass) String() string {
+	name := c.ModelName
+	switch {
+	case c.Part != 0:
+		name = partName(c.Part)
+		if name == "" {
+			name = c.Part.String()
+		}
+	case name == "" && c.MicroArch != "":
+		name = c.MicroArch
+	case name == "":
+		name = "unknown"
+	}
+	return fmt.Sprintf("%s ×%d (%s)", name, c.Count, c.CPUs)
+}
+
+// Summary groups the host's CPUs into classes of CPUs with
+// identical models, features, and caches.
+//
+// Features are compared as sets. Offline CPUs without a
+// description are reported in Offline instead of a class.
+func (v Info) Summary() Summary {
+	var s Summary
+	empty := CPUClass{}.key()
+	index := make(map[string]int)
+	procs := append([]CPU(nil), v.CPUs...)
+	sort.SliceStable(procs, func(i, j int) bool {
+		return procs[i].Proc < procs[j].Proc
+	})
+	for _, c := range procs {
+		class := CPUClass{
+			VendorID:  c.VendorID,
+			Impl:      c.Impl,
+			Arch:      c.Arch,
+			Variant:   c.Variant,
+			Part:      c.Part,
+			Family:    c.Family,
+			Model:     c.Model,
+			Rev:       c.Rev,
+			ModelName: c.ModelName,
+			MicroArch: c.MicroArch,
+			Features:  c.Features,
+		}
+		if c.Cache != (Cache{}) {
+			cache := c.Cache
+			class.Cache = &cache
+		}
+		key := class.key()
+		if !c.Online && key == empty {
+			s.Offline.Set(c.Proc)
+			continue
+		}
+		s.CPUs++
+		i, ok := index[key]
+		if !ok {
+			i = len(s.Classes)
+			index[key] = i
+			s.Classes = append(s.Classes, class)
+		}
+		s.Classes[i].CPUs.Set(c.Proc)
+		s.Classes[i].Count++
+	}
+	return s
+}
+
+// key returns a string that is identical for CPUs in the same
+// class.
+func (c CPUClass) key() string {
+	feats := append([]string(nil), c.Features...)
+	sort.Strings(feats)
+	c.Features = nil
+	var cache Cache
+	if c.Cache != nil {
+		cache = *c.Cache
+		c.Cache = nil
+	}
+	// Convert c to a type without a String method.
+	type fields CPUClass
+	return fmt.Sprintf("%+v %+v %s", fields(c), cache, strings.Join(feats, " "))
+}
@@ -0,0 +1,76 @@
+package sysinfo
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestSummary(t *testing.T) {
+	v := readTestInfo(t, "google_pixel_6")
+	// Shuffle the CPUs and their features.
+	v.CPUs[0], v.CPUs[7] = v.CPUs[7], v.CPUs[0]
+	v.CPUs[1].Features = append([]string{"asimddp"}, v.CPUs[1].Features[:len(v.CPUs[1].Features)-1]...)
+
+	s := v.Summary()
+	if s.CPUs != 8 {
+		t.Fatalf("expected 8 CPUs, got %d", s.CPUs)
+	}
+	want := []string{
+		"Cortex-A55 ×4 (0-3)",
+		"Cortex-A76 ×2 (4-5)",
+		"Cortex-X1 ×2 (6-7)",
+	}
+	if len(s.Classes) != len(want) {
+		t.Fatalf("expected %d classes, got %v", len(want), s.Classes)
+	}
+	for i, c := range s.Classes {
+		if got := c.String(); got != want[i] {
+			t.Fatalf("#%d: expected %q, got %q", i, want[i], got)
+		}
+	}
+
+	v.CPUs[2].Cache.L2 = 1 << 20
+	if n := len(v.Summary().Classes); n != 4 {
+		t.Fatalf("expected a class for the different cache, got %d classes", n)
+	}
+}
+
+func TestSummaryOffline(t *testing.T) {
+	v := readTestInfo(t, "google_pixel_6")
+	v.CPUs = v.CPUs[:4]
+	v.Masks.Present = NewCPUSet(0, 1, 2, 3, 4, 5)
+	v.Masks.Online = NewCPUSet(0, 1, 2, 3)
+	applyCPUMasks(&v)
+
+	s := v.Summary()
+	if s.CPUs != 4 || len(s.Classes) != 1 || s.Classes[0].String() != "Cortex-A55 ×4 (0-3)" {
+		t.Fatalf("unexpected summary: %+v", s)
+	}
+	if got := s.Offline.String(); got != "4-5" {
+		t.Fatalf("expected offline CPUs 4-5, got %q", got)
+	}
+}
+
+func TestSummaryJSON(t *testing.T) {
+	v := readTestInfo(t, "rockpro64")
+	buf, err := json.Marshal(v.Summary())
+	if err != nil {
+		t.Fatal(err)
+	}
+	const want = `{"cpus":6,"classes":[` +
+		`{"cpus":"0-3","count":4,"implementer":"ARM Ltd","arch":8,"part_number":"Cortex-A53 (0xd03)","revision":4,` +
+		`"features":["fp","asimd","evtstrm","aes","pmull","sha1","sha2","crc32","cpuid"]},` +
+		`{"cpus":"4-5","count":2,"implementer":"ARM Ltd","arch":8,"part_number":"Cortex-A72 (0xd08)","revision":2,` +
+		`"features":["fp","asimd","evtstrm","aes","pmull","sha1","sha2","crc32","cpuid"]}],"offline":""}`
+	if string(buf) != want {
+		t.Fatalf("expected\n%s\ngot\n%s", want, buf)
+	}
+
+	var got Summary
+	if err := json.Unmarshal(buf, &got); err != nil {
+		t.Fatal(err)
+	}
+	if len(got.Classes) != 2 || got.Classes[1].Part != CortexA72 || !got.Classes[1].CPUs.Equal(NewCPUSet(4, 5)) {
+		t.Fatalf("unexpected round trip: %+v", got)
+	}
+}
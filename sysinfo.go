@@ -5,6 +5,7 @@ import (
 	"bytes"
 	"encoding"
 	"fmt"
+	"math"
 	"math/bits"
 	"sort"
 	"strconv"
@@ -12,14 +13,64 @@ import (
 )
 
 type Info struct {
+	// SchemaVersion is the version of the JSON encoding. It
+	// is always SchemaVersion.
+	SchemaVersion int `json:"schema_version"`
 	// CPUs is per-cpu information.
 	//
 	// CPUs is sorted by the Proc field in asending order.
-	CPUs []CPU
-	// Misc is any unknown information.
+	//
+	// CPUs that are present but offline are included with
+	// their Online field set to false. /proc/cpuinfo does
+	// not list offline CPUs, so only the fields read from
+	// /sys/devices/system/cpu, such as Proc, Isolated, and
+	// Idle, are set. Use the Online field to skip them.
+	CPUs []CPU `json:"cpus"`
+	// Misc is any unknown information that does not belong
+	// to a CPU, such as the "Hardware" and "Serial" lines
+	// that follow the CPUs in /proc/cpuinfo on some Arm
+	// hosts. Unknown information about a CPU is in its Extra
+	// field.
 	//
 	// Misc is sorted by the Key field in asending order.
-	Misc []Pair
+	Misc []Pair `json:"misc"`
+	// Masks describes which CPUs are present, online,
+	// isolated, and so on.
+	Masks CPUMasks `json:"masks"`
+	// NUMA describes the host's NUMA nodes, if it has NUMA
+	// support.
+	NUMA []NUMANode `json:"numa,omitempty"`
+	// System describes the host's hardware identity.
+	System System `json:"system"`
+	// Kernel is the kernel release, for example
+	// "6.1.0-13-amd64".
+	//
+	// Matches: /proc/sys/kernel/osrelease
+	Kernel string `json:"kernel,omitempty"`
+	// SMBIOS is the decoded SMBIOS table, if it could be
+	// read.
+	SMBIOS *SMBIOS `json:"smbios,omitempty"`
+	// Board describes single-board computers, such as the
+	// Raspberry Pi.
+	Board Board `json:"board"`
+	// Security describes the host's CPU vulnerabilities and
+	// mitigations.
+	Security Security `json:"security"`
+	// Thermal describes the host's temperature sensors.
+	Thermal Thermal `json:"thermal"`
+	// Power describes the host's RAPL power domains.
+	Power Power `json:"power"`
+	// CPUIdle summarizes the CPU idle states.
+	CPUIdle CPUIdle `json:"cpuidle"`
+	// Runtime describes the host's load and uptime at the
+	// time it was detected.
+	Runtime Runtime `json:"runtime"`
+	// Pressure is the host-wide pressure stall information.
+	Pressure Pressure `json:"pressure"`
+	// CgroupPressure is the pressure stall information of the
+	// current process's cgroup, if it is in a cgroup v2
+	// hierarchy.
+	CgroupPressure *Pressure `json:"cgroup_pressure,omitempty"`
 }
 
 // Detect finds the current host information.
@@ -27,7 +78,9 @@ type Info struct {
 // Note that each call to Detect might return different
 // information.
 func Detect() Info {
-	return detect()
+	v := detect()
+	v.SchemaVersion = SchemaVersion
+	return v
 }
 
 // CPU describes a single CPU.
@@ -63,7 +116,23 @@ type CPU struct {
 	// Matches: model name
 	ModelName string `json:"model_name,omitempty"`
 	// MicroArch is the CPU's microarchitecture.
-	MicroArch string `json:"micro_arch"`
+	MicroArch string `json:"micro_arch,omitempty"`
+	// Online is whether the CPU is online.
+	Online bool `json:"online"`
+	// Isolated is whether the CPU has been isolated from the
+	// general scheduler with the isolcpus kernel parameter.
+	Isolated bool `json:"isolated,omitempty"`
+	// Allowed is whether the current process is allowed to
+	// run on the CPU.
+	Allowed bool `json:"allowed,omitempty"`
+	// Idle is the CPU's idle states (C-states), from
+	// shallowest to deepest.
+	Idle []IdleState `json:"idle_states,omitempty"`
+	// Governor is the CPU's cpufreq scaling governor, for
+	// example "performance" or "schedutil".
+	//
+	// Matches: scaling_governor
+	Governor string `json:"governor,omitempty"`
 
 	// ARM
 
@@ -187,6 +256,17 @@ type CPU struct {
 		// PageSize is the size in bytes of each page.
 		PageSize int `json:"page_size,omitempty"`
 	} `json:"tlb,omitempty"`
+
+	// Extra is any unknown information in the CPU's
+	// /proc/cpuinfo block, such as "vmx flags".
+	Extra map[string]string `json:"extra,omitempty"`
+}
+
+// Lookup returns the value of an unknown /proc/cpuinfo key
+// in the CPU's block.
+func (c CPU) Lookup(key string) (string, bool) {
+	v, ok := c.Extra[key]
+	return v, ok
 }
 
 type Cache struct {
@@ -214,7 +294,32 @@ type Cache struct {
 
 // Pair is a miscellaneous piece of data reported by the host.
 type Pair struct {
-	Key, Value string
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+// Lookup returns the value of key in Misc.
+func (v Info) Lookup(key string) (string, bool) {
+	for _, p := range v.Misc {
+		if p.Key == key {
+			return p.Value, true
+		}
+	}
+	return "", false
+}
+
+// LookupInt returns the value of key in Misc as an integer.
+//
+// Values with a "0x" prefix are parsed as hexadecimal. It
+// reports false if the key is missing or its value is not an
+// integer.
+func (v Info) LookupInt(key string) (int64, bool) {
+	s, ok := v.Lookup(key)
+	if !ok {
+		return 0, false
+	}
+	x, err := strconv.ParseInt(s, 0, 64)
+	return x, err == nil
 }
 
 func (c CPU) String() string {
@@ -257,12 +362,34 @@ const (
 
 type Implementer uint8
 
-var _ encoding.TextMarshaler = Implementer(0)
+var (
+	_ encoding.TextMarshaler   = Implementer(0)
+	_ encoding.TextUnmarshaler = (*Implementer)(nil)
+)
 
 func (i Implementer) MarshalText() ([]byte, error) {
 	return []byte(i.String()), nil
 }
 
+func (i *Implementer) UnmarshalText(text []byte) error {
+	v, err := ParseImplementer(string(text))
+	if err != nil {
+		return err
+	}
+	*i = v
+	return nil
+}
+
+// ParseImplementer parses an implementer's name, as returned
+// by its String method, or its number, like "0x41" or
+// "Implementer(65)".
+func ParseImplementer(s string) (Implementer, error) {
+	v, err := parseEnum("Implementer", s, func(i uint8) string {
+		return Implementer(i).String()
+	})
+	return Implementer(v), err
+}
+
 // ARM
 const (
 	ARM926EJS   Part = 0x926 // ARM926EJ-S
@@ -299,6 +426,97 @@ const (
 
 type Part uint16
 
+var (
+	_ encoding.TextMarshaler   = Part(0)
+	_ encoding.TextUnmarshaler = (*Part)(nil)
+	_ fmt.Stringer             = Part(0)
+)
+
+// String returns the part's name and number, like
+// "Cortex-A72 (0xd08)", or just its number if the name is
+// unknown.
+//
+// Part numbers are only unique for a particular implementer,
+// so the name is a best guess. Use CPU.Name for the name of
+// a CPU's part.
+func (p Part) String() string {
+	if name := partName(p); name != "" {
+		return fmt.Sprintf("%s (%#x)", name, uint16(p))
+	}
+	return fmt.Sprintf("%#x", uint16(p))
+}
+
+func (p Part) MarshalText() ([]byte, error) {
+	return []byte(p.String()), nil
+}
+
+func (p *Part) UnmarshalText(text []byte) error {
+	v, err := ParsePart(string(text))
+	if err != nil {
+		return err
+	}
+	*p = v
+	return nil
+}
+
+// ParsePart parses a part in the form returned by its String
+// method, like "Cortex-A72 (0xd08)", a part number, like
+// "0xd08", or a known part name, like "Cortex-A72".
+func ParsePart(s string) (Part, error) {
+	num := s
+	if i := strings.LastIndexByte(s, '('); i >= 0 && strings.HasSuffix(s, ")") {
+		num = s[i+1 : len(s)-1]
+	}
+	if v, err := strconv.ParseUint(num, 0, 16); err == nil {
+		return Part(v), nil
+	}
+	for _, p := range knownParts {
+		if strings.EqualFold(partName(p), s) {
+			return p, nil
+		}
+	}
+	return 0, fmt.Errorf("sysinfo: invalid part %q", s)
+}
+
+// knownParts is every named part.
+var knownParts = append(armParts[:len(armParts):len(armParts)],
+	Firestorm, Icestorm,
+	ThunderX2T99, ThunderX2T99_2, ThunderXT88,
+	A64FX, Carmel, TSV110,
+	Krait, Kryo, Kryo_2, Kryo_3,
+	Kryo2xxGold, Kryo2xxSilver, Kryo3xxGold, Kryo3xxSilver,
+	Kryo4xxGold, Kryo4xxSilver, Falkor, Saphira,
+)
+
+// partName returns the name of a part from any implementer,
+// or the empty string if it is unknown.
+func partName(p Part) string {
+	for _, fn := range []func(Part) string{
+		armPartName,
+		broadcomPartName,
+		fujitsuPartName,
+		nvidiaPartName,
+		hiSiliconPartName,
+		qualcommPartName,
+	} {
+		if name := fn(p); name != "generic" {
+			return name
+		}
+	}
+	return ""
+}
+
+// armParts are the ARM Ltd parts.
+var armParts = []Part{
+	ARM926EJS, ARM11MPCore, ARM1136JS, ARM1156T2S, ARM1176JZS,
+	CortexA8, CortexA9, CortexA15,
+	CortexM0, CortexM3, CortexM4, CortexM55,
+	CortexA34, CortexA35, CortexA53, CortexA55, CortexA57,
+	CortexA72, CortexA73, CortexA75, CortexA76, CortexA77,
+	CortexA78, CortexX1, CortexX1C,
+	NeoverseN1, NeoverseN2, NeoverseV1,
+}
+
 func armPartName(p Part) string {
 	switch p {
 	case ARM926EJS:
@@ -484,15 +702,25 @@ func samsungPartName(p Part) string {
 //
 // See http://www.linfo.org/proc_cpuinfo.html
 func scanProc(o *Info, buf []byte) {
-	var c CPU
+	var (
+		c CPU
+		// inCPU is whether the scanner is inside of
+		// a processor block.
+		inCPU bool
+	)
 	s := bufio.NewScanner(bytes.NewReader(buf))
 	for s.Scan() {
 		k, v := split(s.Text())
 		switch k {
 		case "":
-			o.CPUs = append(o.CPUs, c)
+			if inCPU {
+				o.CPUs = append(o.CPUs, c)
+				inCPU = false
+			}
 		case "processor":
 			c.Proc = atoi(v)
+			c.Extra = nil
+			inCPU = true
 		case "BogoMIPS", "bogomips":
 			c.BogoMIPS = atof(v)
 		case "Features":
@@ -556,15 +784,27 @@ func scanProc(o *Info, buf []byte) {
 		case "TLB size":
 			c.TLB.N, c.TLB.PageSize = parseTLB(v)
 		default:
-			o.Misc = append(o.Misc, Pair{Key: k, Value: v})
+			if !inCPU {
+				o.Misc = append(o.Misc, Pair{Key: k, Value: v})
+				break
+			}
+			if c.Extra == nil {
+				c.Extra = make(map[string]string)
+			}
+			c.Extra[k] = v
 		}
 	}
+	if inCPU {
+		// The last block was not followed by a blank line.
+		o.CPUs = append(o.CPUs, c)
+	}
 	sort.Slice(o.CPUs, func(i, j int) bool {
 		return o.CPUs[i].Proc < o.CPUs[j].Proc
 	})
 	sort.Slice(o.Misc, func(i, j int) bool {
 		return o.Misc[i].Key < o.Misc[j].Key
 	})
+	scanBoard(o)
 }
 
 func parseBool(s string) bool {
@@ -652,6 +892,23 @@ func atof(s string) float64 {
 	return f
 }
 
+// parseEnum parses the text form of an enumerated type named
+// typ: one of its names, the "typ(N)" form that stringer uses
+// for unknown values, or a number.
+func parseEnum(typ, s string, name func(uint8) string) (uint8, error) {
+	for i := 0; i <= math.MaxUint8; i++ {
+		if name(uint8(i)) == s {
+			return uint8(i), nil
+		}
+	}
+	num := strings.TrimSuffix(strings.TrimPrefix(s, typ+"("), ")")
+	v, err := strconv.ParseUint(num, 0, 8)
+	if err != nil {
+		return 0, fmt.Errorf("sysinfo: invalid %s %q", typ, s)
+	}
+	return uint8(v), nil
+}
+
 func atoi(s string) int {
 	x, _ := strconv.ParseUint(s, 0, bits.UintSize)
 	return int(x)
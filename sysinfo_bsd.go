@@ -2,6 +2,28 @@
 
 package sysinfo
 
+import "io"
+
 func detect() Info {
 	return Info{}
 }
+
+func captureHost(w io.Writer) error {
+	return errUnsupported
+}
+
+func readHostThermal() Thermal {
+	return Thermal{}
+}
+
+func readHostPower() Power {
+	return Power{}
+}
+
+func readHostPressure() Pressure {
+	return Pressure{}
+}
+
+func sampleHostCPU() (CPUSample, error) {
+	return CPUSample{}, errUnsupported
+}
@@ -3,6 +3,7 @@ package sysinfo
 import (
 	"encoding/binary"
 	"fmt"
+	"io"
 
 	"golang.org/x/sys/unix"
 )
@@ -76,6 +77,26 @@ func detectM1(o *Info) {
 	)
 }
 
+func captureHost(w io.Writer) error {
+	return errUnsupported
+}
+
+func readHostThermal() Thermal {
+	return Thermal{}
+}
+
+func readHostPower() Power {
+	return Power{}
+}
+
+func readHostPressure() Pressure {
+	return Pressure{}
+}
+
+func sampleHostCPU() (CPUSample, error) {
+	return CPUSample{}, errUnsupported
+}
+
 const debug = false
 
 func sysctl(s string) string {
@@ -1,13 +1,53 @@
 package sysinfo
 
-import "os"
+import (
+	"io"
+	"os"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
 
 func detect() Info {
-	buf, err := os.ReadFile("/proc/cpuinfo")
+	return detectFS(os.DirFS("/"), affinity())
+}
+
+// affinity returns the CPUs the current process is allowed
+// to run on.
+func affinity() CPUSet {
+	var set unix.CPUSet
+	if err := unix.SchedGetaffinity(0, &set); err != nil {
+		return CPUSet{}
+	}
+	return CPUSetFromUnix(&set)
+}
+
+func captureHost(w io.Writer) error {
+	return capture(w, os.DirFS("/"))
+}
+
+func readHostThermal() Thermal {
+	return readThermal(os.DirFS("/"))
+}
+
+func readHostPower() Power {
+	return readPower(os.DirFS("/"))
+}
+
+func readHostPressure() Pressure {
+	return readPressure(os.DirFS("/"))
+}
+
+func sampleHostCPU() (CPUSample, error) {
+	buf, err := os.ReadFile("/proc/stat")
+	if err != nil {
+		return CPUSample{}, err
+	}
+	now := time.Now()
+	s, err := parseProcStat(buf)
 	if err != nil {
-		return Info{}
+		return CPUSample{}, err
 	}
-	var v Info
-	scanProc(&v, buf)
-	return v
+	s.Time = now
+	return s, nil
 }
@@ -1,7 +1,9 @@
 package sysinfo
 
 import (
+	"encoding"
 	"encoding/json"
+	"math"
 	"os"
 	"path/filepath"
 	"reflect"
@@ -11,11 +13,6 @@ import (
 	"github.com/r3labs/diff/v3"
 )
 
-func TestX(t *testing.T) {
-	v := Detect()
-	println(sprint(v))
-}
-
 func TestReadProc(t *testing.T) {
 	split := func(s string) []string {
 		return strings.Split(s, " ")
@@ -67,6 +64,17 @@ func TestReadProc(t *testing.T) {
 					{"Revision", "c03111"},
 					{"Serial", "10000000771c4af4"},
 				},
+				Board: Board{
+					Model:        "Raspberry Pi 4 Model B Rev 1.1",
+					Name:         "Raspberry Pi 4 Model B",
+					Manufacturer: "Sony UK",
+					SoC:          "BCM2711",
+					Memory:       4 << 30,
+					Revision:     "1.1",
+					RevisionCode: "c03111",
+					Hardware:     "BCM2711",
+					Serial:       "10000000771c4af4",
+				},
 			},
 		},
 		{
@@ -302,3 +310,236 @@ func sprint(v interface{}) string {
 	}
 	return string(buf)
 }
+
+func TestJSONRoundTrip(t *testing.T) {
+	ents, err := os.ReadDir("testdata")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, e := range ents {
+		if strings.HasPrefix(e.Name(), "dmi_") {
+			continue
+		}
+		want := readTestInfo(t, e.Name())
+		buf, err := json.Marshal(want)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got Info
+		if err := json.Unmarshal(buf, &got); err != nil {
+			t.Fatalf("%s: %v", e.Name(), err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Fatalf("%s: expected %#v, got %#v", e.Name(), want, got)
+		}
+	}
+
+	buf, err := os.ReadFile(filepath.Join("testdata", "dmi_supermicro_epyc"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := DecodeSMBIOS(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want.Structures = nil
+	buf, err = json.Marshal(want)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got *SMBIOS
+	if err := json.Unmarshal(buf, &got); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %#v, got %#v", want, got)
+	}
+}
+
+func TestParseImplementer(t *testing.T) {
+	for _, tc := range []struct {
+		s    string
+		want Implementer
+	}{
+		{"ARM Ltd", ARMLtd},
+		{"Apple Inc", Apple},
+		{"0x41", ARMLtd},
+		{"65", ARMLtd},
+		{"Implementer(77)", Implementer(77)},
+	} {
+		got, err := ParseImplementer(tc.s)
+		if err != nil {
+			t.Fatalf("%q: %v", tc.s, err)
+		}
+		if got != tc.want {
+			t.Fatalf("%q: expected %v, got %v", tc.s, tc.want, got)
+		}
+	}
+	if _, err := ParseImplementer("Zilog"); err == nil {
+		t.Fatal("expected an error")
+	}
+}
+
+func TestParsePart(t *testing.T) {
+	for _, tc := range []struct {
+		s    string
+		want Part
+	}{
+		{"Cortex-A72 (0xd08)", CortexA72},
+		{"Cortex-A72", CortexA72},
+		{"cortex-a72", CortexA72},
+		{"neoverse-n1", NeoverseN1},
+		{"0xd08", CortexA72},
+		{"3336", CortexA72},
+		{"0xfff", Part(0xfff)},
+		{"Mystery (0x123)", Part(0x123)},
+	} {
+		got, err := ParsePart(tc.s)
+		if err != nil {
+			t.Fatalf("%q: %v", tc.s, err)
+		}
+		if got != tc.want {
+			t.Fatalf("%q: expected %v, got %v", tc.s, tc.want, got)
+		}
+	}
+	if _, err := ParsePart("Cortex-Z9"); err == nil {
+		t.Fatal("expected an error")
+	}
+	for _, p := range []Part{CortexA72, Falkor, Part(0xfff)} {
+		got, err := ParsePart(p.String())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != p {
+			t.Fatalf("expected %v, got %v", p, got)
+		}
+	}
+}
+
+func TestScanProcExtra(t *testing.T) {
+	const cpuinfo = `processor	: 0
+vendor_id	: GenuineIntel
+vmx flags	: vnmi preemption_timer invvpid ept_x_only
+cpu_quirk	: a
+
+processor	: 1
+vendor_id	: GenuineIntel
+vmx flags	: vnmi preemption_timer
+
+Hardware	: Example Board
+Revision	: 0x1f
+Serial		: 00000000deadbeef
+processor	: 2
+vendor_id	: GenuineIntel`
+
+	var v Info
+	scanProc(&v, []byte(cpuinfo))
+	if len(v.CPUs) != 3 {
+		t.Fatalf("expected 3 CPUs, got %d", len(v.CPUs))
+	}
+	want := []map[string]string{
+		{"vmx flags": "vnmi preemption_timer invvpid ept_x_only", "cpu_quirk": "a"},
+		{"vmx flags": "vnmi preemption_timer"},
+		nil,
+	}
+	for i, c := range v.CPUs {
+		if !reflect.DeepEqual(c.Extra, want[i]) {
+			t.Fatalf("#%d: expected %v, got %v", i, want[i], c.Extra)
+		}
+	}
+	if s, ok := v.CPUs[1].Lookup("vmx flags"); !ok || s != "vnmi preemption_timer" {
+		t.Fatalf("unexpected CPU lookup: %q, %t", s, ok)
+	}
+	if _, ok := v.CPUs[1].Lookup("cpu_quirk"); ok {
+		t.Fatal("unexpected cpu_quirk on CPU 1")
+	}
+
+	wantMisc := []Pair{
+		{"Hardware", "Example Board"},
+		{"Revision", "0x1f"},
+		{"Serial", "00000000deadbeef"},
+	}
+	if !reflect.DeepEqual(v.Misc, wantMisc) {
+		t.Fatalf("expected %v, got %v", wantMisc, v.Misc)
+	}
+	if s, ok := v.Lookup("Hardware"); !ok || s != "Example Board" {
+		t.Fatalf("unexpected lookup: %q, %t", s, ok)
+	}
+	if x, ok := v.LookupInt("Revision"); !ok || x != 0x1f {
+		t.Fatalf("unexpected LookupInt: %d, %t", x, ok)
+	}
+	if _, ok := v.LookupInt("Serial"); ok {
+		t.Fatal("expected LookupInt to fail for Serial")
+	}
+	if _, ok := v.Lookup("vmx flags"); ok {
+		t.Fatal("unexpected per-CPU key in Misc")
+	}
+}
+
+func TestEnumTextRoundTrip(t *testing.T) {
+	type enum interface {
+		encoding.TextMarshaler
+		encoding.TextUnmarshaler
+	}
+	for _, tc := range []struct {
+		name string
+		new  func(v int) (enum, func() int)
+		max  int
+	}{
+		{"Implementer", func(v int) (enum, func() int) {
+			x := Implementer(v)
+			return &x, func() int { return int(x) }
+		}, math.MaxUint8},
+		{"Part", func(v int) (enum, func() int) {
+			x := Part(v)
+			return &x, func() int { return int(x) }
+		}, math.MaxUint16},
+		{"ChangeKind", func(v int) (enum, func() int) {
+			x := ChangeKind(v)
+			return &x, func() int { return int(x) }
+		}, math.MaxUint8},
+		{"ChassisType", func(v int) (enum, func() int) {
+			x := ChassisType(v)
+			return &x, func() int { return int(x) }
+		}, math.MaxUint8},
+		{"MemoryType", func(v int) (enum, func() int) {
+			x := MemoryType(v)
+			return &x, func() int { return int(x) }
+		}, math.MaxUint8},
+		{"MemoryFormFactor", func(v int) (enum, func() int) {
+			x := MemoryFormFactor(v)
+			return &x, func() int { return int(x) }
+		}, math.MaxUint8},
+		{"MemoryECC", func(v int) (enum, func() int) {
+			x := MemoryECC(v)
+			return &x, func() int { return int(x) }
+		}, math.MaxUint8},
+		{"SlotUsage", func(v int) (enum, func() int) {
+			x := SlotUsage(v)
+			return &x, func() int { return int(x) }
+		}, math.MaxUint8},
+		{"SlotType", func(v int) (enum, func() int) {
+			x := SlotType(v)
+			return &x, func() int { return int(x) }
+		}, math.MaxUint8},
+		{"VulnStatus", func(v int) (enum, func() int) {
+			x := VulnStatus(v)
+			return &x, func() int { return int(x) }
+		}, math.MaxUint8},
+	} {
+		for v := 0; v <= tc.max; v++ {
+			x, _ := tc.new(v)
+			text, err := x.MarshalText()
+			if err != nil {
+				t.Fatalf("%s(%d): %v", tc.name, v, err)
+			}
+			y, get := tc.new(0)
+			if err := y.UnmarshalText(text); err != nil {
+				t.Fatalf("%s(%d): %q: %v", tc.name, v, text, err)
+			}
+			if got := get(); got != v {
+				t.Errorf("%s(%d): %q decoded as %d", tc.name, v, text, got)
+			}
+		}
+	}
+}
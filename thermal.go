@@ -0,0 +1,307 @@
+package sysinfo
+
+import (
+	"errors"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Thermal describes the host's temperature sensors.
+//
+// On Linux, this information is read from /sys/class/thermal
+// and /sys/class/hwmon.
+type Thermal struct {
+	// Sensors is every temperature sensor.
+	//
+	// Thermal zones are listed first, followed by hwmon
+	// sensors, each in the order the kernel numbered them.
+	// Only the hwmon sensors of CPU drivers (coretemp,
+	// k10temp, zenpower, and cpu_thermal) are included.
+	Sensors []Sensor `json:"sensors,omitempty"`
+
+	// fsys is the file system the sensors were read from.
+	fsys fs.FS
+}
+
+// Sensor is a single temperature sensor.
+type Sensor struct {
+	// Name is the sensor's name, which is the thermal zone's
+	// type or the hwmon sensor's label, for example
+	// "x86_pkg_temp", "Package id 0", "Core 3", or "Tctl".
+	Name string `json:"name"`
+	// Driver is the hwmon driver or thermal zone type, for
+	// example "coretemp", "k10temp", or "cpu-thermal".
+	Driver string `json:"driver,omitempty"`
+	// Source is where the sensor was read from, for example
+	// "thermal_zone0" or "hwmon1/temp2".
+	Source string `json:"source"`
+	// Temp is the current temperature in degrees Celsius.
+	Temp float64 `json:"temp_c"`
+	// Max is the hwmon sensor's high temperature in degrees
+	// Celsius, or zero if unknown.
+	Max float64 `json:"max_c,omitempty"`
+	// Crit is the hwmon sensor's critical temperature in
+	// degrees Celsius, or zero if unknown.
+	Crit float64 `json:"crit_c,omitempty"`
+	// Trips are the thermal zone's trip points.
+	Trips []TripPoint `json:"trips,omitempty"`
+	// Package is the physical package (socket) the sensor
+	// measures, or -1 if unknown or not applicable.
+	Package int `json:"package"`
+	// Core is the core the sensor measures, or -1 if the
+	// sensor is not specific to a core.
+	Core int `json:"core"`
+
+	// input is the file containing the sensor's temperature.
+	input string
+}
+
+// TripPoint is a temperature at which the kernel takes
+// action, such as throttling the CPU.
+type TripPoint struct {
+	// Type is the trip point type, for example "passive",
+	// "active", "hot", or "critical".
+	Type string `json:"type"`
+	// Temp is the trip temperature in degrees Celsius.
+	Temp float64 `json:"temp_c"`
+}
+
+// ReadThermal reads the host's temperature sensors.
+//
+// Use Refresh to cheaply update the temperatures afterward.
+func ReadThermal() Thermal {
+	return readHostThermal()
+}
+
+// Refresh re-reads the current temperature of each sensor.
+//
+// Unlike ReadThermal, it does not search for sensors or read
+// their labels and trip points. Sensors that cannot be read
+// keep their previous temperature, and the first such error
+// is returned.
+//
+// Refresh returns an error if t was not read from the host,
+// for example if it was decoded from JSON.
+func (t *Thermal) Refresh() error {
+	if t.fsys == nil && len(t.Sensors) > 0 {
+		return errNotFromHost
+	}
+	var first error
+	for i := range t.Sensors {
+		s := &t.Sensors[i]
+		v, err := readMilli(t.fsys, s.input)
+		if err != nil {
+			if first == nil {
+				first = err
+			}
+			continue
+		}
+		s.Temp = v
+	}
+	return first
+}
+
+// errNotFromHost is returned when re-reading data that was
+// not read from the host.
+var errNotFromHost = errors.New("sysinfo: not read from the host")
+
+// Package returns the hottest sensor for the package, if any.
+func (t Thermal) Package(pkg int) (Sensor, bool) {
+	var (
+		best  Sensor
+		found bool
+	)
+	for _, s := range t.Sensors {
+		if s.Package == pkg && s.Core < 0 && (!found || s.Temp > best.Temp) {
+			best, found = s, true
+		}
+	}
+	return best, found
+}
+
+// readThermal reads /sys/class/thermal and /sys/class/hwmon.
+//
+// fsys should be rooted at "/".
+func readThermal(fsys fs.FS) Thermal {
+	t := Thermal{fsys: fsys}
+	t.Sensors = append(t.Sensors, readThermalZones(fsys)...)
+	t.Sensors = append(t.Sensors, readHwmon(fsys)...)
+	return t
+}
+
+func readThermalZones(fsys fs.FS) []Sensor {
+	const dir = "sys/class/thermal"
+	var list []Sensor
+	for _, name := range numberedDirs(fsys, dir, "thermal_zone") {
+		zone := path.Join(dir, name)
+		s := Sensor{
+			Name:    readString(fsys, zone+"/type"),
+			Source:  name,
+			Package: -1,
+			Core:    -1,
+			input:   zone + "/temp",
+		}
+		s.Driver = s.Name
+		v, err := readMilli(fsys, s.input)
+		if err != nil {
+			continue
+		}
+		s.Temp = v
+		if s.Name == "cpu-thermal" || s.Name == "cpu_thermal" {
+			// The Raspberry Pi and similar boards have a single
+			// SoC sensor.
+			s.Package = 0
+		}
+		for i := 0; ; i++ {
+			prefix := zone + "/trip_point_" + strconv.Itoa(i)
+			v, err := readMilli(fsys, prefix+"_temp")
+			if err != nil {
+				break
+			}
+			s.Trips = append(s.Trips, TripPoint{
+				Type: readString(fsys, prefix+"_type"),
+				Temp: v,
+			})
+		}
+		list = append(list, s)
+	}
+	return list
+}
+
+// readHwmon reads the temperature sensors of the CPU hwmon
+// devices in /sys/class/hwmon.
+//
+// fsys should be rooted at "/".
+func readHwmon(fsys fs.FS) []Sensor {
+	const dir = "sys/class/hwmon"
+	var (
+		list []Sensor
+		k10  int // number of k10temp devices
+	)
+	for _, name := range numberedDirs(fsys, dir, "hwmon") {
+		mon := path.Join(dir, name)
+		driver := readString(fsys, mon+"/name")
+
+		var sensors []Sensor
+		pkg := -1
+		switch driver {
+		case "k10temp", "zenpower":
+			// One device per package.
+			pkg = k10
+			k10++
+		case "cpu_thermal":
+			pkg = 0
+		case "coretemp":
+		default:
+			// Not a CPU sensor, for example nvme or amdgpu.
+			continue
+		}
+		ents, _ := fs.ReadDir(fsys, mon)
+		for _, e := range ents {
+			in := e.Name()
+			if !strings.HasPrefix(in, "temp") || !strings.HasSuffix(in, "_input") {
+				continue
+			}
+			id := strings.TrimSuffix(in, "_input")
+			s := Sensor{
+				Name:    readString(fsys, mon+"/"+id+"_label"),
+				Driver:  driver,
+				Source:  name + "/" + id,
+				Package: pkg,
+				Core:    -1,
+				input:   mon + "/" + in,
+			}
+			v, err := readMilli(fsys, s.input)
+			if err != nil {
+				continue
+			}
+			s.Temp = v
+			s.Max, _ = readMilli(fsys, mon+"/"+id+"_max")
+			s.Crit, _ = readMilli(fsys, mon+"/"+id+"_crit")
+			if s.Name == "" {
+				s.Name = driver
+			}
+			// coretemp: "Package id 0", "Core 3".
+			if n, ok := labelNumber(s.Name, "Package id "); ok {
+				pkg = n
+			}
+			if n, ok := labelNumber(s.Name, "Core "); ok {
+				s.Core = n
+			}
+			sensors = append(sensors, s)
+		}
+		// coretemp has one device per package, so every sensor
+		// belongs to the package named by the "Package id"
+		// sensor.
+		for i := range sensors {
+			sensors[i].Package = pkg
+		}
+		sort.Slice(sensors, func(i, j int) bool {
+			return tempIndex(sensors[i].Source) < tempIndex(sensors[j].Source)
+		})
+		list = append(list, sensors...)
+	}
+	return list
+}
+
+// numberedDirs returns the entries in dir named prefix
+// followed by a number, in numerical order.
+func numberedDirs(fsys fs.FS, dir, prefix string) []string {
+	ents, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil
+	}
+	var names []string
+	for _, e := range ents {
+		if _, ok := labelNumber(e.Name(), prefix); ok {
+			names = append(names, e.Name())
+		}
+	}
+	sort.Slice(names, func(i, j int) bool {
+		a, _ := labelNumber(names[i], prefix)
+		b, _ := labelNumber(names[j], prefix)
+		return a < b
+	})
+	return names
+}
+
+// labelNumber parses s as prefix followed by a number.
+func labelNumber(s, prefix string) (int, bool) {
+	if !strings.HasPrefix(s, prefix) {
+		return 0, false
+	}
+	n, err := strconv.Atoi(s[len(prefix):])
+	return n, err == nil
+}
+
+// tempIndex returns N for a source ending in "tempN".
+func tempIndex(source string) int {
+	n, _ := labelNumber(path.Base(source), "temp")
+	return n
+}
+
+// readString reads a single-line file.
+func readString(fsys fs.FS, name string) string {
+	buf, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(buf))
+}
+
+// readMilli reads a file containing an integer in
+// thousandths and returns it in whole units.
+func readMilli(fsys fs.FS, name string) (float64, error) {
+	buf, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return 0, err
+	}
+	v, err := strconv.ParseInt(strings.TrimSpace(string(buf)), 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	return float64(v) / 1000, nil
+}
@@ -0,0 +1,93 @@
+package sysinfo
+
+import (
+	"reflect"
+	"testing"
+	"testing/fstest"
+)
+
+func TestReadThermal(t *testing.T) {
+	file := func(s string) *fstest.MapFile {
+		return &fstest.MapFile{Data: []byte(s + "\n")}
+	}
+	fsys := fstest.MapFS{
+		"sys/class/thermal/thermal_zone0/type":              file("acpitz"),
+		"sys/class/thermal/thermal_zone0/temp":              file("27800"),
+		"sys/class/thermal/thermal_zone0/trip_point_0_type": file("critical"),
+		"sys/class/thermal/thermal_zone0/trip_point_0_temp": file("119000"),
+		"sys/class/thermal/thermal_zone10/type":             file("x86_pkg_temp"),
+		"sys/class/thermal/thermal_zone10/temp":             file("45000"),
+		"sys/class/thermal/thermal_zone2/type":              file("iwlwifi_1"),
+		"sys/class/thermal/cooling_device0/type":            file("Processor"),
+
+		"sys/class/hwmon/hwmon1/name":         file("coretemp"),
+		"sys/class/hwmon/hwmon1/temp1_input":  file("45000"),
+		"sys/class/hwmon/hwmon1/temp1_label":  file("Package id 1"),
+		"sys/class/hwmon/hwmon1/temp1_max":    file("84000"),
+		"sys/class/hwmon/hwmon1/temp1_crit":   file("100000"),
+		"sys/class/hwmon/hwmon1/temp2_input":  file("43000"),
+		"sys/class/hwmon/hwmon1/temp2_label":  file("Core 0"),
+		"sys/class/hwmon/hwmon1/temp10_input": file("44000"),
+		"sys/class/hwmon/hwmon1/temp10_label": file("Core 8"),
+
+		"sys/class/hwmon/hwmon2/name":        file("nvme"),
+		"sys/class/hwmon/hwmon2/temp1_input": file("38850"),
+		"sys/class/hwmon/hwmon2/temp1_label": file("Composite"),
+
+		"sys/class/hwmon/hwmon3/name":        file("k10temp"),
+		"sys/class/hwmon/hwmon3/temp1_input": file("52125"),
+		"sys/class/hwmon/hwmon3/temp1_label": file("Tctl"),
+		"sys/class/hwmon/hwmon3/temp3_input": file("48750"),
+		"sys/class/hwmon/hwmon3/temp3_label": file("Tccd1"),
+
+		"sys/class/hwmon/hwmon4/name":        file("cpu_thermal"),
+		"sys/class/hwmon/hwmon4/temp1_input": file("61835"),
+	}
+	got := readThermal(fsys)
+
+	want := []Sensor{
+		{Name: "acpitz", Driver: "acpitz", Source: "thermal_zone0", Temp: 27.8, Package: -1, Core: -1,
+			Trips: []TripPoint{{Type: "critical", Temp: 119}}, input: "sys/class/thermal/thermal_zone0/temp"},
+		{Name: "x86_pkg_temp", Driver: "x86_pkg_temp", Source: "thermal_zone10", Temp: 45, Package: -1, Core: -1,
+			input: "sys/class/thermal/thermal_zone10/temp"},
+		{Name: "Package id 1", Driver: "coretemp", Source: "hwmon1/temp1", Temp: 45, Max: 84, Crit: 100, Package: 1, Core: -1,
+			input: "sys/class/hwmon/hwmon1/temp1_input"},
+		{Name: "Core 0", Driver: "coretemp", Source: "hwmon1/temp2", Temp: 43, Package: 1, Core: 0,
+			input: "sys/class/hwmon/hwmon1/temp2_input"},
+		{Name: "Core 8", Driver: "coretemp", Source: "hwmon1/temp10", Temp: 44, Package: 1, Core: 8,
+			input: "sys/class/hwmon/hwmon1/temp10_input"},
+		{Name: "Tctl", Driver: "k10temp", Source: "hwmon3/temp1", Temp: 52.125, Package: 0, Core: -1,
+			input: "sys/class/hwmon/hwmon3/temp1_input"},
+		{Name: "Tccd1", Driver: "k10temp", Source: "hwmon3/temp3", Temp: 48.75, Package: 0, Core: -1,
+			input: "sys/class/hwmon/hwmon3/temp3_input"},
+		{Name: "cpu_thermal", Driver: "cpu_thermal", Source: "hwmon4/temp1", Temp: 61.835, Package: 0, Core: -1,
+			input: "sys/class/hwmon/hwmon4/temp1_input"},
+	}
+	if !reflect.DeepEqual(got.Sensors, want) {
+		t.Fatalf("expected %#v, got %#v", want, got.Sensors)
+	}
+
+	if s, ok := got.Package(1); !ok || s.Name != "Package id 1" {
+		t.Fatalf("unexpected package sensor: %#v", s)
+	}
+	if s, ok := got.Package(0); !ok || s.Name != "cpu_thermal" {
+		t.Fatalf("unexpected package sensor: %#v", s)
+	}
+
+	fsys["sys/class/hwmon/hwmon1/temp2_input"] = file("70000")
+	delete(fsys, "sys/class/thermal/thermal_zone0/temp")
+	if err := got.Refresh(); err == nil {
+		t.Fatal("expected an error")
+	}
+	if got.Sensors[0].Temp != 27.8 {
+		t.Fatalf("expected the previous temperature, got %v", got.Sensors[0].Temp)
+	}
+	if got.Sensors[3].Temp != 70 {
+		t.Fatalf("expected 70, got %v", got.Sensors[3].Temp)
+	}
+
+	decoded := Thermal{Sensors: got.Sensors}
+	if err := decoded.Refresh(); err == nil {
+		t.Fatal("expected an error")
+	}
+}
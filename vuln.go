@@ -0,0 +1,217 @@
+package sysinfo
+
+import (
+	"io/fs"
+	"sort"
+	"strings"
+)
+
+// Security describes the host's CPU vulnerabilities and the
+// state of their mitigations.
+//
+// On Linux, this information is read from
+// /sys/devices/system/cpu/vulnerabilities, /proc/cmdline,
+// and /sys/devices/system/cpu/smt.
+type Security struct {
+	// Vulnerabilities is the status of each vulnerability
+	// known to the kernel.
+	//
+	// Vulnerabilities is sorted by the Name field in
+	// ascending order.
+	Vulnerabilities []Vulnerability `json:"vulnerabilities,omitempty"`
+	// Overrides are the kernel command line parameters that
+	// change how vulnerabilities are mitigated, for example
+	// "mitigations=off" or "nosmt".
+	Overrides []string `json:"overrides,omitempty"`
+	// SMT is the state of simultaneous multithreading
+	// control, for example "on", "off", "forceoff", or
+	// "notsupported".
+	//
+	// Matches: smt/control
+	SMT string `json:"smt,omitempty"`
+}
+
+// Lookup returns the vulnerability with the provided name,
+// which is either its sysfs name (for example "meltdown") or
+// its /proc/cpuinfo bug name (for example "cpu_meltdown").
+func (s Security) Lookup(name string) (Vulnerability, bool) {
+	for _, v := range s.Vulnerabilities {
+		if v.Name == name || v.Bug == name {
+			return v, true
+		}
+	}
+	return Vulnerability{}, false
+}
+
+// Vulnerability is the status of a single CPU vulnerability.
+type Vulnerability struct {
+	// Name is the vulnerability's sysfs name, for example
+	// "spectre_v2".
+	Name string `json:"name"`
+	// Bug is the vulnerability's name in the CPU.Bugs field,
+	// for example "cpu_meltdown" for "meltdown".
+	Bug string `json:"bug,omitempty"`
+	// Status is whether the CPU is affected and, if so,
+	// whether the vulnerability is mitigated.
+	Status VulnStatus `json:"status"`
+	// Detail is the text following the status, for example
+	// "PTI" for "Mitigation: PTI".
+	Detail string `json:"detail,omitempty"`
+	// Raw is the unparsed status reported by the kernel.
+	Raw string `json:"raw"`
+}
+
+// VulnStatus is the status of a CPU vulnerability.
+type VulnStatus uint8
+
+const (
+	VulnUnknown     VulnStatus = iota // Unknown
+	VulnNotAffected                   // Not affected
+	VulnVulnerable                    // Vulnerable
+	VulnMitigated                     // Mitigation
+)
+
+func (s VulnStatus) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+func (s *VulnStatus) UnmarshalText(text []byte) error {
+	v, err := parseEnum("VulnStatus", string(text), func(i uint8) string {
+		return VulnStatus(i).String()
+	})
+	if err != nil {
+		return err
+	}
+	*s = VulnStatus(v)
+	return nil
+}
+
+// vulnBugs maps sysfs vulnerability names to /proc/cpuinfo
+// bug names where they differ.
+var vulnBugs = map[string]string{
+	"meltdown":               "cpu_meltdown",
+	"tsx_async_abort":        "taa",
+	"spec_rstack_overflow":   "srso",
+	"gather_data_sampling":   "gds",
+	"reg_file_data_sampling": "rfds",
+	"spectre_bhi":            "bhi",
+}
+
+// readSecurity reads /sys/devices/system/cpu/vulnerabilities
+// and the related kernel parameters.
+//
+// fsys should be rooted at "/".
+func readSecurity(fsys fs.FS) Security {
+	var s Security
+	const dir = "sys/devices/system/cpu/vulnerabilities"
+	ents, _ := fs.ReadDir(fsys, dir)
+	for _, e := range ents {
+		buf, err := fs.ReadFile(fsys, dir+"/"+e.Name())
+		if err != nil {
+			continue
+		}
+		s.Vulnerabilities = append(s.Vulnerabilities,
+			parseVuln(e.Name(), string(buf)))
+	}
+	sort.Slice(s.Vulnerabilities, func(i, j int) bool {
+		return s.Vulnerabilities[i].Name < s.Vulnerabilities[j].Name
+	})
+	if buf, err := fs.ReadFile(fsys, "proc/cmdline"); err == nil {
+		s.Overrides = parseMitigationOverrides(string(buf))
+	}
+	if buf, err := fs.ReadFile(fsys, "sys/devices/system/cpu/smt/control"); err == nil {
+		s.SMT = strings.TrimSpace(string(buf))
+	}
+	return s
+}
+
+// parseVuln parses the contents of a file in
+// /sys/devices/system/cpu/vulnerabilities, for example
+//
+//	Mitigation: PTI
+//	Not affected
+//	Vulnerable: Clear CPU buffers attempted, no microcode; SMT Host state unknown
+//	KVM: Mitigation: VMX disabled
+func parseVuln(name, s string) Vulnerability {
+	s = strings.TrimSpace(s)
+	v := Vulnerability{
+		Name: name,
+		Bug:  name,
+		Raw:  s,
+	}
+	if bug, ok := vulnBugs[name]; ok {
+		v.Bug = bug
+	}
+	// itlb_multihit is prefixed with the hypervisor.
+	s = strings.TrimPrefix(s, "KVM: ")
+	switch {
+	case strings.HasPrefix(s, "Not affected"):
+		v.Status = VulnNotAffected
+	case strings.HasPrefix(s, "Mitigation"):
+		v.Status = VulnMitigated
+		v.Detail = vulnDetail(s, "Mitigation")
+	case strings.HasPrefix(s, "Vulnerable"):
+		v.Status = VulnVulnerable
+		v.Detail = vulnDetail(s, "Vulnerable")
+	case strings.HasPrefix(s, "Processor vulnerable"):
+		v.Status = VulnVulnerable
+		v.Detail = vulnDetail(s, "Processor vulnerable")
+	default:
+		v.Status = VulnUnknown
+		v.Detail = vulnDetail(s, "Unknown")
+	}
+	return v
+}
+
+func vulnDetail(s, prefix string) string {
+	s = strings.TrimPrefix(s, prefix)
+	s = strings.TrimPrefix(s, ":")
+	return strings.TrimSpace(s)
+}
+
+// mitigationParams are the kernel parameters that control
+// CPU vulnerability mitigations.
+//
+// See Documentation/admin-guide/kernel-parameters.txt in the
+// Linux kernel.
+var mitigationParams = map[string]bool{
+	"gather_data_sampling":        true,
+	"kvm.nx_huge_pages":           true,
+	"l1tf":                        true,
+	"mds":                         true,
+	"mitigations":                 true,
+	"mmio_stale_data":             true,
+	"nopti":                       true,
+	"nosmt":                       true,
+	"nospec_store_bypass_disable": true,
+	"nospectre_bhb":               true,
+	"nospectre_v1":                true,
+	"nospectre_v2":                true,
+	"pti":                         true,
+	"reg_file_data_sampling":      true,
+	"retbleed":                    true,
+	"spec_rstack_overflow":        true,
+	"spec_store_bypass_disable":   true,
+	"spectre_bhi":                 true,
+	"spectre_v2":                  true,
+	"spectre_v2_user":             true,
+	"srbds":                       true,
+	"tsx":                         true,
+	"tsx_async_abort":             true,
+}
+
+// parseMitigationOverrides returns the parameters in the
+// kernel command line that control mitigations.
+func parseMitigationOverrides(cmdline string) []string {
+	var list []string
+	for _, p := range strings.Fields(cmdline) {
+		key := p
+		if i := strings.IndexByte(p, '='); i >= 0 {
+			key = p[:i]
+		}
+		if mitigationParams[key] {
+			list = append(list, p)
+		}
+	}
+	return list
+}
@@ -0,0 +1,63 @@
+package sysinfo
+
+import (
+	"reflect"
+	"testing"
+	"testing/fstest"
+)
+
+func TestReadSecurity(t *testing.T) {
+	// From the intel_skylake_ubuntu host.
+	const dir = "sys/devices/system/cpu/vulnerabilities/"
+	fsys := fstest.MapFS{
+		dir + "itlb_multihit":                {Data: []byte("KVM: Mitigation: VMX unsupported\n")},
+		dir + "l1tf":                         {Data: []byte("Mitigation: PTE Inversion\n")},
+		dir + "mds":                          {Data: []byte("Vulnerable: Clear CPU buffers attempted, no microcode; SMT Host state unknown\n")},
+		dir + "meltdown":                     {Data: []byte("Mitigation: PTI\n")},
+		dir + "spec_store_bypass":            {Data: []byte("Mitigation: Speculative Store Bypass disabled via prctl and seccomp\n")},
+		dir + "spectre_v1":                   {Data: []byte("Mitigation: usercopy/swapgs barriers and __user pointer sanitization\n")},
+		dir + "spectre_v2":                   {Data: []byte("Mitigation: Full generic retpoline, IBPB: conditional, IBRS_FW, STIBP: disabled, RSB filling\n")},
+		dir + "srbds":                        {Data: []byte("Unknown: Dependent on hypervisor status\n")},
+		dir + "tsx_async_abort":              {Data: []byte("Not affected\n")},
+		"proc/cmdline":                       {Data: []byte("BOOT_IMAGE=/boot/vmlinuz root=/dev/sda1 ro mitigations=auto,nosmt nosmt spectre_v2=retpoline quiet\n")},
+		"sys/devices/system/cpu/smt/control": {Data: []byte("off\n")},
+	}
+	got := readSecurity(fsys)
+
+	vuln := func(name, bug string, status VulnStatus, detail, raw string) Vulnerability {
+		return Vulnerability{Name: name, Bug: bug, Status: status, Detail: detail, Raw: raw}
+	}
+	want := Security{
+		Vulnerabilities: []Vulnerability{
+			vuln("itlb_multihit", "itlb_multihit", VulnMitigated, "VMX unsupported", "KVM: Mitigation: VMX unsupported"),
+			vuln("l1tf", "l1tf", VulnMitigated, "PTE Inversion", "Mitigation: PTE Inversion"),
+			vuln("mds", "mds", VulnVulnerable, "Clear CPU buffers attempted, no microcode; SMT Host state unknown", "Vulnerable: Clear CPU buffers attempted, no microcode; SMT Host state unknown"),
+			vuln("meltdown", "cpu_meltdown", VulnMitigated, "PTI", "Mitigation: PTI"),
+			vuln("spec_store_bypass", "spec_store_bypass", VulnMitigated, "Speculative Store Bypass disabled via prctl and seccomp", "Mitigation: Speculative Store Bypass disabled via prctl and seccomp"),
+			vuln("spectre_v1", "spectre_v1", VulnMitigated, "usercopy/swapgs barriers and __user pointer sanitization", "Mitigation: usercopy/swapgs barriers and __user pointer sanitization"),
+			vuln("spectre_v2", "spectre_v2", VulnMitigated, "Full generic retpoline, IBPB: conditional, IBRS_FW, STIBP: disabled, RSB filling", "Mitigation: Full generic retpoline, IBPB: conditional, IBRS_FW, STIBP: disabled, RSB filling"),
+			vuln("srbds", "srbds", VulnUnknown, "Dependent on hypervisor status", "Unknown: Dependent on hypervisor status"),
+			vuln("tsx_async_abort", "taa", VulnNotAffected, "", "Not affected"),
+		},
+		Overrides: []string{"mitigations=auto,nosmt", "nosmt", "spectre_v2=retpoline"},
+		SMT:       "off",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %#v, got %#v", want, got)
+	}
+
+	// Every bug in the fixture's cpuinfo should be linked to
+	// a vulnerability, except for swapgs, which the kernel
+	// reports under spectre_v1.
+	for _, bug := range []string{"cpu_meltdown", "spectre_v1", "spectre_v2", "spec_store_bypass", "l1tf", "mds", "itlb_multihit", "srbds"} {
+		if _, ok := got.Lookup(bug); !ok {
+			t.Fatalf("%s: not found", bug)
+		}
+	}
+	if v, ok := got.Lookup("meltdown"); !ok || v.Bug != "cpu_meltdown" {
+		t.Fatalf("unexpected lookup result: %#v", v)
+	}
+	if v := parseVuln("spectre_bhi", "Mitigation: BHI_DIS_S\n"); v.Bug != "bhi" {
+		t.Fatalf("expected bug %q, got %q", "bhi", v.Bug)
+	}
+}
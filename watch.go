@@ -0,0 +1,467 @@
+package sysinfo
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Metric is a set of dynamic host metrics sampled by Watch.
+type Metric uint8
+
+const (
+	// MetricFreq is the current frequency of each CPU.
+	MetricFreq Metric = 1 << iota
+	// MetricTemp is the temperature of each sensor.
+	MetricTemp
+	// MetricUtil is CPU utilization.
+	MetricUtil
+	// MetricOnline is the set of online CPUs.
+	MetricOnline
+	// MetricMemory is available memory.
+	MetricMemory
+
+	// MetricAll is every metric.
+	MetricAll = MetricFreq | MetricTemp | MetricUtil | MetricOnline | MetricMemory
+)
+
+// Update is a sample of dynamic host metrics.
+//
+// An Update and everything it refers to are reused by later
+// samples. It is only valid until the next Update is received
+// or, for WatchFunc, until the callback returns. Use Clone to
+// retain it.
+type Update struct {
+	// Time is when the sample was taken.
+	Time time.Time
+	// Changed is the set of metrics that differ from the
+	// previous Update. It may be empty.
+	//
+	// Every sampled metric is set in the first Update, except
+	// for MetricUtil, which requires two samples.
+	Changed Metric
+	// Freq is the current frequency in MHz of each CPU,
+	// indexed by CPU.Proc, or zero if unknown or offline.
+	//
+	// Matches: cpuN/cpufreq/scaling_cur_freq
+	Freq []float64
+	// Temp is the temperature in degrees Celsius of each
+	// sensor in Sensors.
+	Temp []float64
+	// Sensors describes the sensors in Temp. It does not
+	// change between samples and its Temp fields are not
+	// updated.
+	Sensors []Sensor
+	// Util is the CPU utilization since the previous sample.
+	Util Utilization
+	// Online is the set of online CPUs.
+	Online CPUSet
+	// MemAvailable is an estimate of the memory in bytes
+	// available for starting new applications without
+	// swapping.
+	//
+	// Matches: MemAvailable
+	MemAvailable uint64
+
+	// stat holds the CPU times the next Util is computed
+	// from.
+	stat CPUSample
+}
+
+// Clone returns a copy of u that is not reused by later
+// samples.
+func (u *Update) Clone() *Update {
+	c := *u
+	c.Freq = append([]float64(nil), u.Freq...)
+	c.Temp = append([]float64(nil), u.Temp...)
+	c.Util.CPUs = append([]CPUUtil(nil), u.Util.CPUs...)
+	c.Online = u.Online.Clone()
+	c.stat = CPUSample{}
+	return &c
+}
+
+// Watch samples metrics every interval until ctx is done.
+//
+// If no metrics are provided, every metric is sampled. The
+// first Update is sent immediately.
+//
+// Watch keeps the files it reads open and, after the first few
+// samples, does not allocate. The returned channel is closed
+// and the files are closed after ctx is done. Updates are not
+// buffered: a slow receiver delays the next sample rather than
+// queuing samples.
+//
+// Watch returns an error if interval is not positive or if
+// none of the metrics can be sampled on this host.
+func Watch(ctx context.Context, interval time.Duration, metrics ...Metric) (<-chan *Update, error) {
+	if interval <= 0 {
+		return nil, errWatchInterval
+	}
+	w, err := newWatcher("/", metricSet(metrics))
+	if err != nil {
+		return nil, err
+	}
+	ch := make(chan *Update)
+	go func() {
+		defer close(ch)
+		w.run(ctx, interval, func(u *Update) bool {
+			select {
+			case ch <- u:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		})
+	}()
+	return ch, nil
+}
+
+// WatchFunc is like Watch, but calls fn with each Update
+// instead of sending it over a channel.
+//
+// WatchFunc blocks until ctx is done and then returns
+// ctx.Err().
+func WatchFunc(ctx context.Context, interval time.Duration, fn func(*Update), metrics ...Metric) error {
+	if interval <= 0 {
+		return errWatchInterval
+	}
+	w, err := newWatcher("/", metricSet(metrics))
+	if err != nil {
+		return err
+	}
+	w.run(ctx, interval, func(u *Update) bool {
+		fn(u)
+		return true
+	})
+	return ctx.Err()
+}
+
+var errWatchInterval = errors.New("sysinfo: non-positive watch interval")
+
+func metricSet(metrics []Metric) Metric {
+	var m Metric
+	for _, x := range metrics {
+		m |= x
+	}
+	if m == 0 {
+		m = MetricAll
+	}
+	return m & MetricAll
+}
+
+// watcher samples metrics from open files.
+type watcher struct {
+	// metrics is the set of metrics that can be sampled.
+	metrics Metric
+	root    string
+	// freq is the scaling_cur_freq file of each possible CPU,
+	// indexed by CPU number. Files for CPUs that have never
+	// been online are nil.
+	freq    []*os.File
+	temp    []*os.File
+	sensors []Sensor
+	stat    *os.File
+	online  *os.File
+	meminfo *os.File
+	buf     []byte
+	// ring is the pair of Updates handed out in turn.
+	ring [2]Update
+	n    int
+}
+
+func newWatcher(root string, metrics Metric) (*watcher, error) {
+	w := &watcher{
+		root: root,
+		buf:  make([]byte, 4096),
+	}
+	if metrics&MetricUtil != 0 {
+		if w.stat = w.open("proc/stat"); w.stat != nil {
+			w.metrics |= MetricUtil
+		}
+	}
+	if metrics&(MetricOnline|MetricFreq) != 0 {
+		w.online = w.open("sys/devices/system/cpu/online")
+		if w.online != nil {
+			w.metrics |= metrics & MetricOnline
+		}
+	}
+	if metrics&MetricFreq != 0 {
+		possible := w.cpuList("sys/devices/system/cpu/possible")
+		w.freq = make([]*os.File, possible.Max()+1)
+		possible.Range(func(cpu int) bool {
+			w.freq[cpu] = w.open(freqFile(cpu))
+			if w.freq[cpu] != nil {
+				w.metrics |= MetricFreq
+			}
+			return true
+		})
+	}
+	if metrics&MetricTemp != 0 {
+		for _, s := range readThermal(os.DirFS(root)).Sensors {
+			if f := w.open(s.input); f != nil {
+				w.sensors = append(w.sensors, s)
+				w.temp = append(w.temp, f)
+			}
+		}
+		if len(w.temp) > 0 {
+			w.metrics |= MetricTemp
+		}
+	}
+	if metrics&MetricMemory != 0 {
+		if w.meminfo = w.open("proc/meminfo"); w.meminfo != nil {
+			w.metrics |= MetricMemory
+		}
+	}
+	if w.metrics == 0 {
+		w.close()
+		return nil, errUnsupported
+	}
+	for i := range w.ring {
+		u := &w.ring[i]
+		u.Sensors = w.sensors
+		u.Freq = make([]float64, len(w.freq))
+		u.Temp = make([]float64, len(w.temp))
+	}
+	return w, nil
+}
+
+func freqFile(cpu int) string {
+	return fmt.Sprintf("sys/devices/system/cpu/cpu%d/cpufreq/scaling_cur_freq", cpu)
+}
+
+// open opens name relative to the watcher's root, or returns
+// nil if it cannot be opened.
+func (w *watcher) open(name string) *os.File {
+	f, err := os.Open(filepath.Join(w.root, filepath.FromSlash(name)))
+	if err != nil {
+		return nil
+	}
+	return f
+}
+
+func (w *watcher) cpuList(name string) CPUSet {
+	f := w.open(name)
+	if f == nil {
+		return CPUSet{}
+	}
+	defer f.Close()
+	var set CPUSet
+	if b, err := w.read(f); err == nil {
+		set.parseList(b)
+	}
+	return set
+}
+
+func (w *watcher) close() {
+	files := []*os.File{w.stat, w.online, w.meminfo}
+	files = append(files, w.freq...)
+	files = append(files, w.temp...)
+	for _, f := range files {
+		if f != nil {
+			f.Close()
+		}
+	}
+}
+
+// run samples every interval and calls send with each Update
+// until ctx is done or send returns false.
+func (w *watcher) run(ctx context.Context, interval time.Duration, send func(*Update) bool) {
+	defer w.close()
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		if !send(w.sample()) {
+			return
+		}
+		select {
+		case <-t.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// sample reads each metric into the next Update in the ring.
+func (w *watcher) sample() *Update {
+	prev := &w.ring[(w.n+1)%2]
+	u := &w.ring[w.n%2]
+	first := w.n == 0
+	w.n++
+
+	u.Time = time.Now()
+	u.Changed = 0
+	if w.metrics&MetricOnline != 0 || w.freq != nil {
+		w.sampleOnline(u, prev)
+		if w.metrics&MetricOnline != 0 && (first || !u.Online.Equal(prev.Online)) {
+			u.Changed |= MetricOnline
+		}
+	}
+	if w.metrics&MetricFreq != 0 {
+		w.sampleFreq(u)
+		if first || !floatsEqual(u.Freq, prev.Freq) {
+			u.Changed |= MetricFreq
+		}
+	}
+	if w.metrics&MetricTemp != 0 {
+		for i, f := range w.temp {
+			u.Temp[i] = prev.Temp[i]
+			if b, err := w.read(f); err == nil {
+				if v, ok := parseMilli(b); ok {
+					u.Temp[i] = v
+				}
+			}
+		}
+		if first || !floatsEqual(u.Temp, prev.Temp) {
+			u.Changed |= MetricTemp
+		}
+	}
+	if w.metrics&MetricUtil != 0 {
+		b, err := w.read(w.stat)
+		if err == nil {
+			err = u.stat.parse(b)
+		}
+		if err != nil {
+			u.stat.Time = prev.stat.Time
+			u.stat.Total = prev.stat.Total
+			u.stat.CPUs = append(u.stat.CPUs[:0], prev.stat.CPUs...)
+		} else {
+			u.stat.Time = u.Time
+		}
+		u.Util.Total = CPUUtil{Proc: -1}
+		u.Util.CPUs = u.Util.CPUs[:0]
+		if err == nil && !prev.stat.Time.IsZero() {
+			u.stat.utilizationInto(&u.Util, prev.stat)
+			u.Changed |= MetricUtil
+		}
+	}
+	if w.metrics&MetricMemory != 0 {
+		u.MemAvailable = prev.MemAvailable
+		if b, err := w.read(w.meminfo); err == nil {
+			if v, ok := memAvailable(b); ok {
+				u.MemAvailable = v
+			}
+		}
+		if first || u.MemAvailable != prev.MemAvailable {
+			u.Changed |= MetricMemory
+		}
+	}
+	return u
+}
+
+func (w *watcher) sampleOnline(u, prev *Update) {
+	if w.online == nil {
+		return
+	}
+	b, err := w.read(w.online)
+	if err != nil || !u.Online.parseList(b) {
+		u.Online.w = append(u.Online.w[:0], prev.Online.w...)
+		return
+	}
+	// Open the cpufreq files of CPUs that have come online
+	// since they were first opened.
+	u.Online.Range(func(cpu int) bool {
+		if cpu < len(w.freq) && w.freq[cpu] == nil {
+			w.freq[cpu] = w.open(freqFile(cpu))
+		}
+		return true
+	})
+}
+
+func (w *watcher) sampleFreq(u *Update) {
+	for cpu, f := range w.freq {
+		u.Freq[cpu] = 0
+		if f == nil || (w.online != nil && !u.Online.Has(cpu)) {
+			continue
+		}
+		b, err := w.read(f)
+		if err != nil {
+			continue
+		}
+		if v, ok := parseUintBytes(bytes.TrimSpace(b)); ok {
+			u.Freq[cpu] = float64(v) / 1000
+		}
+	}
+}
+
+// read reads the whole file from the start, reusing w.buf.
+//
+// The result is valid until the next call to read.
+func (w *watcher) read(f *os.File) ([]byte, error) {
+	for {
+		n, err := f.ReadAt(w.buf, 0)
+		if err != nil && err != io.EOF {
+			return nil, err
+		}
+		if n < len(w.buf) {
+			return w.buf[:n], nil
+		}
+		w.buf = make([]byte, 2*len(w.buf))
+	}
+}
+
+// memAvailable returns the MemAvailable line of /proc/meminfo
+// in bytes.
+//
+// It should look like
+//
+//	MemAvailable:   12345678 kB
+func memAvailable(buf []byte) (uint64, bool) {
+	const key = "MemAvailable:"
+	for len(buf) > 0 {
+		line := buf
+		if i := bytes.IndexByte(buf, '\n'); i >= 0 {
+			line, buf = buf[:i], buf[i+1:]
+		} else {
+			buf = nil
+		}
+		if len(line) < len(key) || string(line[:len(key)]) != key {
+			continue
+		}
+		f, rest := nextField(line[len(key):])
+		v, ok := parseUintBytes(f)
+		if !ok {
+			return 0, false
+		}
+		if unit, _ := nextField(rest); string(unit) == "kB" {
+			v *= 1024
+		}
+		return v, true
+	}
+	return 0, false
+}
+
+// parseMilli parses a file containing a signed integer in
+// thousandths, such as millidegrees Celsius.
+func parseMilli(b []byte) (float64, bool) {
+	b = bytes.TrimSpace(b)
+	neg := len(b) > 0 && b[0] == '-'
+	if neg {
+		b = b[1:]
+	}
+	v, ok := parseUintBytes(b)
+	if !ok {
+		return 0, false
+	}
+	x := float64(v) / 1000
+	if neg {
+		x = -x
+	}
+	return x, true
+}
+
+func floatsEqual(a, b []float64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,165 @@
+package sysinfo
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// writeFiles writes each file relative to dir.
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, data := range files {
+		p := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func watchFiles() map[string]string {
+	return map[string]string{
+		"proc/stat":                                            "cpu  200 0 100 700 0 0 0 0 0 0\ncpu0 100 0 50 350 0 0 0 0 0 0\ncpu1 100 0 50 350 0 0 0 0 0 0\n",
+		"proc/meminfo":                                         "MemTotal:       16384 kB\nMemFree:         1024 kB\nMemAvailable:    8192 kB\n",
+		"sys/devices/system/cpu/possible":                      "0-2\n",
+		"sys/devices/system/cpu/online":                        "0-1\n",
+		"sys/class/thermal/thermal_zone0/type":                 "x86_pkg_temp\n",
+		"sys/class/thermal/thermal_zone0/temp":                 "45000\n",
+		"sys/devices/system/cpu/cpu0/cpufreq/scaling_cur_freq": "2400000\n",
+		"sys/devices/system/cpu/cpu1/cpufreq/scaling_cur_freq": "1200000\n",
+	}
+}
+
+func TestWatcher(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, watchFiles())
+
+	w, err := newWatcher(dir, MetricAll)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.close()
+
+	u := w.sample()
+	if want := MetricAll &^ MetricUtil; u.Changed != want {
+		t.Fatalf("expected %b, got %b", want, u.Changed)
+	}
+	if want := []float64{2400, 1200, 0}; !floatsEqual(u.Freq, want) {
+		t.Fatalf("expected %v, got %v", want, u.Freq)
+	}
+	if len(u.Sensors) != 1 || u.Sensors[0].Name != "x86_pkg_temp" {
+		t.Fatalf("unexpected sensors: %#v", u.Sensors)
+	}
+	if want := []float64{45}; !floatsEqual(u.Temp, want) {
+		t.Fatalf("expected %v, got %v", want, u.Temp)
+	}
+	if got := u.Online.String(); got != "0-1" {
+		t.Fatalf("expected %q, got %q", "0-1", got)
+	}
+	if u.MemAvailable != 8192*1024 {
+		t.Fatalf("expected %d, got %d", 8192*1024, u.MemAvailable)
+	}
+
+	// CPU 2 comes online, one CPU is busy, and the rest is
+	// unchanged.
+	writeFiles(t, dir, map[string]string{
+		"proc/stat":                     "cpu  300 0 100 800 0 0 0 0 0 0\ncpu0 200 0 50 350 0 0 0 0 0 0\ncpu1 100 0 50 450 0 0 0 0 0 0\n",
+		"sys/devices/system/cpu/online": "0-2\n",
+		"sys/devices/system/cpu/cpu2/cpufreq/scaling_cur_freq": "800000\n",
+	})
+	u = w.sample()
+	if want := MetricUtil | MetricOnline | MetricFreq; u.Changed != want {
+		t.Fatalf("expected %b, got %b", want, u.Changed)
+	}
+	if want := []float64{2400, 1200, 800}; !floatsEqual(u.Freq, want) {
+		t.Fatalf("expected %v, got %v", want, u.Freq)
+	}
+	if len(u.Util.CPUs) != 2 || u.Util.CPUs[0].Busy() != 1 || u.Util.CPUs[1].Busy() != 0 {
+		t.Fatalf("unexpected utilization: %#v", u.Util)
+	}
+	if got := u.Util.Total.Busy(); got != 0.5 {
+		t.Fatalf("expected 0.5, got %v", got)
+	}
+
+	u = w.sample()
+	if u.Changed != MetricUtil {
+		t.Fatalf("expected %b, got %b", MetricUtil, u.Changed)
+	}
+}
+
+func TestWatcherAllocs(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, watchFiles())
+
+	w, err := newWatcher(dir, MetricAll)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.close()
+	for i := 0; i < 2; i++ {
+		w.sample()
+	}
+	if n := testing.AllocsPerRun(100, func() { w.sample() }); n != 0 {
+		t.Fatalf("expected 0 allocations, got %v", n)
+	}
+}
+
+func TestWatcherCancel(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, watchFiles())
+
+	w, err := newWatcher(dir, MetricMemory)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	ch := make(chan *Update)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		w.run(ctx, time.Millisecond, func(u *Update) bool {
+			select {
+			case ch <- u:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		})
+	}()
+	for i := 0; i < 3; i++ {
+		u := <-ch
+		if u.MemAvailable != 8192*1024 {
+			t.Fatalf("expected %d, got %d", 8192*1024, u.MemAvailable)
+		}
+	}
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("watcher did not stop")
+	}
+	if _, err := w.meminfo.Stat(); err == nil {
+		t.Fatal("expected meminfo to be closed")
+	}
+}
+
+func TestWatchUnsupported(t *testing.T) {
+	if _, err := newWatcher(t.TempDir(), MetricAll); err == nil {
+		t.Fatal("expected an error")
+	}
+}
+
+func TestWatchInterval(t *testing.T) {
+	ctx := context.Background()
+	if _, err := Watch(ctx, 0); err == nil {
+		t.Fatal("expected an error")
+	}
+	if err := WatchFunc(ctx, -time.Second, func(*Update) {}); err == nil {
+		t.Fatal("expected an error")
+	}
+}